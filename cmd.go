@@ -81,6 +81,7 @@ type commandSyncDb struct {
 	verbose   bool
 	noInfo    bool
 	rtOnError bool
+	schema    string
 }
 
 // parse orm command line arguments.
@@ -91,6 +92,7 @@ func (d *commandSyncDb) Parse(args []string) {
 	flagSet.StringVar(&name, "db", "default", "DataBase alias name")
 	flagSet.BoolVar(&d.force, "force", false, "drop tables before create")
 	flagSet.BoolVar(&d.verbose, "v", false, "verbose info")
+	flagSet.StringVar(&d.schema, "schema", "", "create the schema and its tables in it")
 	flagSet.Parse(args)
 
 	d.al = getDbAlias(name)
@@ -98,13 +100,37 @@ func (d *commandSyncDb) Parse(args []string) {
 
 // run orm line command.
 func (d *commandSyncDb) Run() error {
+	db := d.al.DB
+
+	if d.schema != "" {
+		query, err := d.al.DbBaser.CreateSchemaSQL(d.schema)
+		if err != nil {
+			if d.rtOnError {
+				return err
+			}
+			fmt.Printf("    %s\n", err.Error())
+			return nil
+		}
+		if !d.noInfo {
+			fmt.Printf("create schema `%s`\n", d.schema)
+		}
+		_, err = db.Exec(query)
+		if d.verbose {
+			fmt.Printf("    %s\n\n", query)
+		}
+		if err != nil {
+			if d.rtOnError {
+				return err
+			}
+			fmt.Printf("    %s\n", err.Error())
+		}
+	}
+
 	var drops []string
 	if d.force {
-		drops = getDbDropSQL(d.al)
+		drops = getDbDropSQL(d.al, d.schema)
 	}
 
-	db := d.al.DB
-
 	if d.force {
 		for i, mi := range modelCache.allOrdered() {
 			query := drops[i]
@@ -124,9 +150,15 @@ func (d *commandSyncDb) Run() error {
 		}
 	}
 
-	sqls, indexes := getDbCreateSQL(d.al)
+	sqls, indexes := getDbCreateSQL(d.al, d.schema)
 
-	tables, err := d.al.DbBaser.GetTables(db)
+	var tables map[string]bool
+	var err error
+	if d.schema != "" {
+		tables, err = d.al.DbBaser.GetSchemaTables(db, d.schema)
+	} else {
+		tables, err = d.al.DbBaser.GetTables(db)
+	}
 	if err != nil {
 		if d.rtOnError {
 			return err
@@ -146,7 +178,12 @@ func (d *commandSyncDb) Run() error {
 			}
 
 			var fields []*fieldInfo
-			columns, err := d.al.DbBaser.GetColumns(db, mi.table)
+			var columns map[string][3]string
+			if d.schema != "" {
+				columns, err = d.al.DbBaser.GetSchemaColumns(db, d.schema, mi.table)
+			} else {
+				columns, err = d.al.DbBaser.GetColumns(db, mi.table)
+			}
 			if err != nil {
 				if d.rtOnError {
 					return err
@@ -161,7 +198,7 @@ func (d *commandSyncDb) Run() error {
 			}
 
 			for _, fi := range fields {
-				query := getColumnAddQuery(d.al, fi)
+				query := getColumnAddQuery(d.al, fi, d.schema)
 
 				if strings.Contains(query, "%COL%") {
 					query = strings.Replace(query, "%COL%", fi.column, -1)
@@ -256,7 +293,7 @@ func (d *commandSQLAll) Parse(args []string) {
 
 // run orm line command.
 func (d *commandSQLAll) Run() error {
-	sqls, indexes := getDbCreateSQL(d.al)
+	sqls, indexes := getDbCreateSQL(d.al, "")
 	var all []string
 	for i, mi := range modelCache.allOrdered() {
 		queries := []string{sqls[i]}
@@ -292,3 +329,21 @@ func RunSyncdb(name string, force bool, verbose bool) error {
 	cmd.rtOnError = true
 	return cmd.Run()
 }
+
+// RunSyncdbWithSchema run syncdb command line in schema.
+// the schema is created when missing, then the tables are created in it,
+// e.g. for a tenant queried by Ormer.UsingSchema.
+// only postgres, opengauss, greenplum and sqlserver support it.
+func RunSyncdbWithSchema(name, schema string, force bool, verbose bool) error {
+	BootStrap()
+
+	al := getDbAlias(name)
+	cmd := new(commandSyncDb)
+	cmd.al = al
+	cmd.schema = schema
+	cmd.force = force
+	cmd.noInfo = !verbose
+	cmd.verbose = verbose
+	cmd.rtOnError = true
+	return cmd.Run()
+}
@@ -0,0 +1,412 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Ormgen generates column constants and typed filter builders of orm models,
+// so field paths are checked by the compiler instead of panicking at runtime.
+//
+// add to the package of the models:
+//
+//	//go:generate go run github.com/libra9z/orm/cmd/ormgen -type User,Profile
+//
+// and use the generated code:
+//
+//	qs.SetCond(UserFields.Profile.Age.Gt(18))       // Filter("Profile__Age__gt", 18)
+//	qs.OrderBy(UserFields.UserName.Desc())          // OrderBy("-UserName")
+//	o.Raw("SELECT " + UserColumnUserName + " FROM user")
+//
+// without -type every struct with an orm tag is a model.
+// relations are followed -depth levels deep, related models must be in the same package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const ormPkg = "github.com/libra9z/orm"
+
+var (
+	typeNames = flag.String("type", "", "comma separated model names, default all structs with orm tags")
+	output    = flag.String("output", "orm_fields_gen.go", "output file name")
+	depth     = flag.Int("depth", 2, "levels of relations to follow")
+	strategy  = flag.String("strategy", "snakeString", "column name strategy, snakeString or snakeStringWithAcronym")
+)
+
+// a field of a model.
+type modelField struct {
+	name   string
+	column string // empty for reverse and m2m relations
+	rel    string // related model in the package
+}
+
+// a model parsed from source.
+type model struct {
+	name   string
+	fields []modelField
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: ormgen [flags] [directory]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var names []string
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	src, err := generate(dir, names, *output, *depth, *strategy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ormgen: %s\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "ormgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// parse the package in dir and generate the source of the models.
+func generate(dir string, names []string, output string, depth int, strategy string) ([]byte, error) {
+	var nameFn func(string) string
+	switch strategy {
+	case "snakeString":
+		nameFn = snakeString
+	case "snakeStringWithAcronym":
+		nameFn = snakeStringWithAcronym
+	default:
+		return nil, fmt.Errorf("unknown name strategy `%s`", strategy)
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != output
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		if pkg != nil {
+			return nil, fmt.Errorf("more than one package in `%s`", dir)
+		}
+		pkg = p
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("no go files in `%s`", dir)
+	}
+
+	structs := make(map[string]*ast.StructType)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if st, ok := ts.Type.(*ast.StructType); ok && ts.TypeParams == nil {
+					structs[ts.Name.Name] = st
+				}
+			}
+		}
+	}
+
+	p := &modelParser{structs: structs, nameFn: nameFn, models: make(map[string]*model)}
+	if len(names) == 0 {
+		for name, st := range structs {
+			if p.hasOrmTag(st) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no models found in `%s`", dir)
+	}
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if _, ok := structs[name]; !ok {
+			return nil, fmt.Errorf("model `%s` not found in `%s`", name, dir)
+		}
+		names[i] = name
+	}
+
+	g := &generator{parser: p, types: make(map[string]bool)}
+	return g.generate(pkg.Name, names, depth)
+}
+
+// parse models from struct declarations.
+type modelParser struct {
+	structs map[string]*ast.StructType
+	nameFn  func(string) string
+	models  map[string]*model
+}
+
+// check a struct or its embedded structs has an orm tag.
+func (p *modelParser) hasOrmTag(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if _, ok := ormTag(f); ok {
+			return true
+		}
+		if len(f.Names) == 0 {
+			if est, ok := p.structs[typeName(f.Type)]; ok && est != st && p.hasOrmTag(est) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// get the model of a struct.
+func (p *modelParser) model(name string) *model {
+	if m, ok := p.models[name]; ok {
+		return m
+	}
+	m := &model{name: name}
+	p.models[name] = m
+	m.fields = p.fields(p.structs[name], map[string]bool{name: true})
+	return m
+}
+
+// get the fields of a struct, embedded structs are flattened like the orm does.
+func (p *modelParser) fields(st *ast.StructType, seen map[string]bool) []modelField {
+	var fields []modelField
+	for _, f := range st.Fields.List {
+		tag, _ := ormTag(f)
+		attrs, tags := parseTag(tag)
+		if attrs["-"] {
+			continue
+		}
+		if len(f.Names) == 0 {
+			name := typeName(f.Type)
+			if est, ok := p.structs[name]; ok && !seen[name] {
+				seen[name] = true
+				fields = append(fields, p.fields(est, seen)...)
+			}
+			continue
+		}
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			mf := modelField{name: ident.Name}
+			rel, reverse := tags["rel"], tags["reverse"]
+			if rel != "" || reverse != "" {
+				if name := typeName(f.Type); p.structs[name] != nil {
+					mf.rel = name
+				}
+			}
+			switch {
+			case reverse != "" || rel == "m2m":
+			case tags["column"] != "":
+				mf.column = tags["column"]
+			case rel != "":
+				mf.column = p.nameFn(ident.Name) + "_id"
+			default:
+				mf.column = p.nameFn(ident.Name)
+			}
+			fields = append(fields, mf)
+		}
+	}
+	return fields
+}
+
+// write the generated source.
+type generator struct {
+	parser *modelParser
+	buf    bytes.Buffer
+	types  map[string]bool
+	queue  [][2]interface{}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) generate(pkgName string, names []string, depth int) ([]byte, error) {
+	g.printf("// Code generated by ormgen. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", pkgName)
+	g.printf("import %q\n", ormPkg)
+
+	for _, name := range names {
+		m := g.parser.model(name)
+		g.printf("\n// columns of %s.\nconst (\n", name)
+		for _, f := range m.fields {
+			if f.column != "" {
+				g.printf("\t%sColumn%s = %q\n", name, f.name, f.column)
+			}
+		}
+		g.printf(")\n")
+
+		g.printf("\n// %sFields are the typed field paths of %s.\n", name, name)
+		g.printf("var %sFields = %s(\"\")\n", name, g.use(name, depth))
+	}
+
+	for len(g.queue) > 0 {
+		item := g.queue[0]
+		g.queue = g.queue[1:]
+		g.writeType(item[0].(string), item[1].(int))
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %s", err)
+	}
+	return src, nil
+}
+
+// get the constructor of the fields type of model at depth, queueing the type.
+func (g *generator) use(name string, depth int) string {
+	typ := fieldsType(name, depth)
+	if !g.types[typ] {
+		g.types[typ] = true
+		g.queue = append(g.queue, [2]interface{}{name, depth})
+	}
+	return "new" + strings.ToUpper(typ[:1]) + typ[1:]
+}
+
+// write the fields type of model at depth and its constructor.
+func (g *generator) writeType(name string, depth int) {
+	m := g.parser.model(name)
+	typ := fieldsType(name, depth)
+
+	g.printf("\n// field paths of %s, relations are followed %d levels deep.\n", name, depth)
+	g.printf("type %s struct {\n\torm.FieldPath\n", typ)
+	for _, f := range m.fields {
+		if f.rel != "" && depth > 0 {
+			g.printf("\t%s %s\n", f.name, fieldsType(f.rel, depth-1))
+		} else {
+			g.printf("\t%s orm.FieldPath\n", f.name)
+		}
+	}
+	g.printf("}\n")
+
+	ctor := g.use(name, depth)
+	g.printf("\nfunc %s(path orm.FieldPath) %s {\n\treturn %s{\n\t\tFieldPath: path,\n", ctor, typ, typ)
+	for _, f := range m.fields {
+		if f.rel != "" && depth > 0 {
+			g.printf("\t\t%s: %s(path.Join(%q)),\n", f.name, g.use(f.rel, depth-1), f.name)
+		} else {
+			g.printf("\t\t%s: path.Join(%q),\n", f.name, f.name)
+		}
+	}
+	g.printf("\t}\n}\n")
+}
+
+// name of the fields type of model at depth.
+func fieldsType(name string, depth int) string {
+	return strings.ToLower(name[:1]) + name[1:] + "Fields" + strconv.Itoa(depth)
+}
+
+// get the orm tag of a field.
+func ormTag(f *ast.Field) (string, bool) {
+	if f.Tag == nil {
+		return "", false
+	}
+	tag, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return "", false
+	}
+	return reflect.StructTag(tag).Lookup("orm")
+}
+
+// parse orm tag like the orm does, attrs have no value and tags have one.
+func parseTag(data string) (attrs map[string]bool, tags map[string]string) {
+	attrs = make(map[string]bool)
+	tags = make(map[string]string)
+	for _, v := range strings.Split(data, ";") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if i := strings.Index(v, "("); i > 0 && strings.HasSuffix(v, ")") {
+			tags[strings.ToLower(v[:i])] = v[i+1 : len(v)-1]
+		} else {
+			attrs[strings.ToLower(v)] = true
+		}
+	}
+	return
+}
+
+// get the type name of Model, *Model or []*Model.
+func typeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return typeName(t.X)
+	case *ast.ArrayType:
+		return typeName(t.Elt)
+	}
+	return ""
+}
+
+// snake string, XxYy to xx_yy , XxYY to xx_y_y
+func snakeString(s string) string {
+	data := make([]byte, 0, len(s)*2)
+	j := false
+	num := len(s)
+	for i := 0; i < num; i++ {
+		d := s[i]
+		if i > 0 && d >= 'A' && d <= 'Z' && j {
+			data = append(data, '_')
+		}
+		if d != '_' {
+			j = true
+		}
+		data = append(data, d)
+	}
+	return strings.ToLower(string(data[:]))
+}
+
+// snake string with acronym, XxYY to xx_yy
+func snakeStringWithAcronym(s string) string {
+	data := make([]byte, 0, len(s)*2)
+	num := len(s)
+	for i := 0; i < num; i++ {
+		d := s[i]
+		before := false
+		after := false
+		if i > 0 {
+			before = s[i-1] >= 'a' && s[i-1] <= 'z'
+		}
+		if i+1 < num {
+			after = s[i+1] >= 'a' && s[i+1] <= 'z'
+		}
+		if i > 0 && d >= 'A' && d <= 'Z' && (before || after) {
+			data = append(data, '_')
+		}
+		data = append(data, d)
+	}
+	return strings.ToLower(string(data[:]))
+}
@@ -0,0 +1,99 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testModels = `package models
+
+type Base struct {
+	Created int64
+}
+
+type User struct {
+	Base
+	ID       int      ` + "`orm:\"column(id)\"`" + `
+	UserName string   ` + "`orm:\"size(30);unique\"`" + `
+	Profile  *Profile ` + "`orm:\"rel(one)\"`" + `
+	Posts    []*Post  ` + "`orm:\"reverse(many)\"`" + `
+	Skip     string   ` + "`orm:\"-\"`" + `
+	secret   string
+}
+
+type Profile struct {
+	ID   int    ` + "`orm:\"column(id)\"`" + `
+	Age  int16
+	User *User  ` + "`orm:\"reverse(one)\"`" + `
+}
+
+type Post struct {
+	ID   int    ` + "`orm:\"column(id)\"`" + `
+	User *User  ` + "`orm:\"rel(fk)\"`" + `
+	Tags []*Tag ` + "`orm:\"rel(m2m)\"`" + `
+}
+
+type Tag struct {
+	ID   int    ` + "`orm:\"column(id)\"`" + `
+	Name string
+}
+
+type notModel struct {
+	Name string
+}
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(testModels), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generate(dir, nil, "orm_fields_gen.go", 1, "snakeString")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(src)
+	for _, want := range []string{
+		`UserColumnCreated  = "created"`,
+		`UserColumnUserName = "user_name"`,
+		`UserColumnProfile  = "profile_id"`,
+		`PostColumnUser = "user_id"`,
+		`var UserFields = newUserFields1("")`,
+		`Profile  profileFields0`,
+		`Profile:   newProfileFields0(path.Join("Profile")),`,
+		`User orm.FieldPath`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generated source has no %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"UserColumnPosts", "UserColumnSkip", "secret", "PostColumnTags", "notModel"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("generated source has %q:\n%s", unwanted, out)
+		}
+	}
+
+	if _, err := generate(dir, []string{"Missing"}, "orm_fields_gen.go", 1, "snakeString"); err == nil {
+		t.Error("unknown model is not an error")
+	}
+	if _, err := generate(dir, nil, "orm_fields_gen.go", 1, "camel"); err == nil {
+		t.Error("unknown name strategy is not an error")
+	}
+}
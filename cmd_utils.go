@@ -27,7 +27,8 @@ type dbIndex struct {
 }
 
 // create database drop sql.
-func getDbDropSQL(al *alias) (sqls []string) {
+// non-empty schema replaces the schema of every model.
+func getDbDropSQL(al *alias, schema string) (sqls []string) {
 	if len(modelCache.cache) == 0 {
 		fmt.Println("no Model found, need register your model")
 		os.Exit(2)
@@ -36,6 +37,7 @@ func getDbDropSQL(al *alias) (sqls []string) {
 	Q := al.DbBaser.TableQuote()
 
 	for _, mi := range modelCache.allOrdered() {
+		mi = mi.inSchema(schema)
 		if mi.schema == "" {
 			sqls = append(sqls, fmt.Sprintf(`DROP TABLE IF EXISTS %s%s%s`, Q, mi.table, Q))
 		} else {
@@ -101,18 +103,32 @@ checkColumn:
 		} else {
 			col = fmt.Sprintf(s, fi.digits, fi.decimals)
 		}
-	case TypeJSONField:
-		if al.Driver != DRPostgres {
-			fieldType = TypeVarCharField
-			goto checkColumn
-		}
-		col = T["json"]
-	case TypeJsonbField:
-		if al.Driver != DRPostgres {
+	case TypeJSONField, TypeJsonbField:
+		if !fi.toJSON {
+			// plain string json fields keep their column types
+			if al.Driver != DRPostgres {
+				fieldType = TypeVarCharField
+				goto checkColumn
+			}
+			if fieldType == TypeJsonbField {
+				col = T["jsonb"]
+			} else {
+				col = T["json"]
+			}
+		} else if fieldType == TypeJsonbField && T["jsonb"] != "" {
+			col = T["jsonb"]
+		} else if T["json"] != "" {
+			col = T["json"]
+		} else if al.Driver == DROracle || al.Driver == DRDameng {
+			// marshalled struct/map/slice may be longer than varchar2
+			col = "CLOB"
+		} else if T["string-text"] != "" && !strings.Contains(T["string-text"], "%d") {
+			// marshalled struct/map/slice may be longer than varchar
+			col = T["string-text"]
+		} else {
 			fieldType = TypeVarCharField
 			goto checkColumn
 		}
-		col = T["jsonb"]
 	case RelForeignKey, RelOneToOne:
 		fieldType = fi.relModelInfo.fields.pk.fieldType
 		fieldSize = fi.relModelInfo.fields.pk.size
@@ -123,8 +139,9 @@ checkColumn:
 }
 
 // create alter sql string.
-func getColumnAddQuery(al *alias, fi *fieldInfo) string {
+func getColumnAddQuery(al *alias, fi *fieldInfo, schema string) string {
 	Q := al.DbBaser.TableQuote()
+	mi := fi.mi.inSchema(schema)
 	typ := getColumnTyp(al, fi)
 
 	if !fi.null {
@@ -133,15 +150,15 @@ func getColumnAddQuery(al *alias, fi *fieldInfo) string {
 
 	smt := ""
 
-	if fi.mi.schema == "" {
+	if mi.schema == "" {
 		smt = fmt.Sprintf("ALTER TABLE %s%s%s ADD COLUMN %s%s%s %s %s",
-			Q, fi.mi.table, Q,
+			Q, mi.table, Q,
 			Q, fi.column, Q,
 			typ, getColumnDefault(fi),
 		)
 	} else {
 		smt = fmt.Sprintf("ALTER TABLE %s%s%s.%s%s%s ADD COLUMN %s%s%s %s %s",
-			Q, fi.mi.schema, Q, Q, fi.mi.table, Q,
+			Q, mi.schema, Q, Q, mi.table, Q,
 			Q, fi.column, Q,
 			typ, getColumnDefault(fi),
 		)
@@ -151,7 +168,8 @@ func getColumnAddQuery(al *alias, fi *fieldInfo) string {
 }
 
 // create database creation string.
-func getDbCreateSQL(al *alias) (sqls []string, tableIndexes map[string][]dbIndex) {
+// non-empty schema replaces the schema of every model.
+func getDbCreateSQL(al *alias, schema string) (sqls []string, tableIndexes map[string][]dbIndex) {
 	if len(modelCache.cache) == 0 {
 		fmt.Println("no Model found, need register your model")
 		os.Exit(2)
@@ -167,12 +185,17 @@ func getDbCreateSQL(al *alias) (sqls []string, tableIndexes map[string][]dbIndex
 		if mi.aliasName != al.Name {
 			continue
 		}
+		mi = mi.inSchema(schema)
 		sql := fmt.Sprintf("-- %s\n", strings.Repeat("-", 50))
 		sql += fmt.Sprintf("--  Table Structure for `%s`\n", mi.fullName)
 		sql += fmt.Sprintf("-- %s\n", strings.Repeat("-", 50))
 
 		if al.Driver == DROracle || al.Driver == DRSqlserver {
-			sql += fmt.Sprintf("CREATE TABLE %s%s%s (\n", Q, mi.table, Q)
+			if mi.schema == "" {
+				sql += fmt.Sprintf("CREATE TABLE %s%s%s (\n", Q, mi.table, Q)
+			} else {
+				sql += fmt.Sprintf("CREATE TABLE %s%s%s.%s%s%s (\n", Q, mi.schema, Q, Q, mi.table, Q)
+			}
 		} else {
 			if mi.schema == "" {
 				sql += fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s%s%s (\n", Q, mi.table, Q)
@@ -344,6 +367,10 @@ func getColumnDefault(fi *fieldInfo) string {
 		t = " DEFAULT %s "
 		d = "FALSE"
 	case TypeJSONField, TypeJsonbField:
+		// a marshalled slice or struct has no meaningful '{}' default
+		if fi.toJSON && !fi.colDefault {
+			return v
+		}
 		d = "{}"
 	}
 
@@ -16,6 +16,7 @@ package orm
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -125,6 +126,27 @@ func (d *dbBase) collectFieldValue(mi *modelInfo, fi *fieldInfo, ind reflect.Val
 		if fi.isFielder {
 			f := field.Addr().Interface().(Fielder)
 			value = f.RawValue()
+		} else if fi.toJSON {
+			vu := field
+			switch field.Kind() {
+			case reflect.Ptr, reflect.Map, reflect.Slice:
+				if field.IsNil() {
+					if fi.null {
+						vu = reflect.Value{}
+					} else if field.Kind() == reflect.Map {
+						vu = reflect.MakeMap(field.Type())
+					} else if field.Kind() == reflect.Slice {
+						vu = reflect.MakeSlice(field.Type(), 0, 0)
+					}
+				}
+			}
+			if vu.IsValid() {
+				data, err := json.Marshal(vu.Interface())
+				if err != nil {
+					return nil, fmt.Errorf("field `%s` json marshal failed, %s", fi.fullName, err)
+				}
+				value = string(data)
+			}
 		} else {
 			switch fi.fieldType {
 			case TypeBooleanField:
@@ -315,7 +337,7 @@ func (d *dbBase) InsertStmt(stmt stmtQuerier, mi *modelInfo, ind reflect.Value,
 }
 
 // query sql ,read records and persist in dbBaser.
-func (d *dbBase) Read(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location, cols []string, isForUpdate bool) error {
+func (d *dbBase) Read(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location, cols []string, lock *RowLock, tenant interface{}) error {
 	var whereCols []string
 	var args []interface{}
 
@@ -336,6 +358,7 @@ func (d *dbBase) Read(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Lo
 		whereCols = []string{pkColumn}
 		args = append(args, pkValue)
 	}
+	whereCols, args = appendTenantWhere(mi, tenant, whereCols, args)
 
 	Q := d.ins.TableQuote()
 
@@ -346,17 +369,20 @@ func (d *dbBase) Read(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Lo
 	sep = fmt.Sprintf("%s = ? AND %s", Q, Q)
 	wheres := strings.Join(whereCols, sep)
 
-	forUpdate := ""
-	if isForUpdate {
-		forUpdate = "FOR UPDATE"
+	hint, forUpdate := "", ""
+	if lock != nil {
+		var err error
+		if hint, forUpdate, err = d.ins.RowLockSQL(lock, nil); err != nil {
+			return err
+		}
 	}
 
 	query := ""
 
 	if mi.schema == "" {
-		query = fmt.Sprintf("SELECT %s%s%s FROM %s%s%s WHERE %s%s%s = ? %s", Q, sels, Q, Q, mi.table, Q, Q, wheres, Q, forUpdate)
+		query = fmt.Sprintf("SELECT %s%s%s FROM %s%s%s%s WHERE %s%s%s = ? %s", Q, sels, Q, Q, mi.table, Q, hint, Q, wheres, Q, forUpdate)
 	} else {
-		query = fmt.Sprintf("SELECT %s%s%s FROM %s%s%s.%s%s%s WHERE %s%s%s = ? %s", Q, sels, Q, Q, mi.schema, Q, Q, mi.table, Q, Q, wheres, Q, forUpdate)
+		query = fmt.Sprintf("SELECT %s%s%s FROM %s%s%s.%s%s%s%s WHERE %s%s%s = ? %s", Q, sels, Q, Q, mi.schema, Q, Q, mi.table, Q, hint, Q, wheres, Q, forUpdate)
 	}
 
 	refs := make([]interface{}, colsNum)
@@ -464,6 +490,159 @@ func (d *dbBase) InsertMulti(q dbQuerier, mi *modelInfo, sind reflect.Value, bul
 	return cnt, err
 }
 
+// update the models in sind with different values per row, bulk models per statement.
+// if cols is empty, all columns except pk are updated.
+// rows of a tenant Ormer are only updated in the tenant.
+func (d *dbBase) UpdateMulti(q dbQuerier, mi *modelInfo, sind reflect.Value, bulk int, a *alias, cols []string, tenant interface{}) (int64, error) {
+	if len(cols) == 0 {
+		cols = mi.fields.dbcols
+	}
+
+	var (
+		cnt   int64
+		names []string
+		rows  = make([][]interface{}, 0, bulk)
+	)
+
+	length := sind.Len()
+	for i := 1; i <= length; i++ {
+		ind := reflect.Indirect(sind.Index(i - 1))
+
+		pkName, pkValue, ok := getExistPk(mi, ind)
+		if !ok {
+			return cnt, ErrMissPK
+		}
+
+		var setNames []string
+		vus, _, err := d.collectValues(mi, ind, cols, true, false, &setNames, a.TZ)
+		if err != nil {
+			return cnt, err
+		}
+		row := make([]interface{}, 0, len(vus)+1)
+		row = append(row, pkValue)
+		for j, name := range setNames {
+			if name != pkName {
+				row = append(row, vus[j])
+			}
+		}
+
+		if names == nil {
+			names = make([]string, 0, len(setNames)+1)
+			names = append(names, pkName)
+			for _, name := range setNames {
+				if name != pkName {
+					names = append(names, name)
+				}
+			}
+			if len(names) == 1 {
+				return cnt, ErrArgs
+			}
+		}
+		if len(row) != len(names) {
+			return cnt, ErrArgs
+		}
+		rows = append(rows, row)
+
+		if len(rows) == bulk || length == i {
+			query, args := d.ins.UpdateMultiSQL(mi, names, rows, a, tenant)
+			res, err := q.Exec(query, args...)
+			if err != nil {
+				return cnt, err
+			}
+			num, err := res.RowsAffected()
+			if err != nil {
+				return cnt, err
+			}
+			cnt += num
+			rows = rows[:0]
+		}
+	}
+
+	return cnt, nil
+}
+
+// get the batched update sql of rows.
+// names[0] and the first value of each row are the pk, every column is set by CASE pk WHEN ? THEN ? END.
+func (d *dbBase) UpdateMultiSQL(mi *modelInfo, names []string, rows [][]interface{}, a *alias, tenant interface{}) (string, []interface{}) {
+	Q := d.ins.TableQuote()
+	pkName := names[0]
+
+	args := make([]interface{}, 0, len(rows)*(len(names)-1)*2+len(rows))
+	sets := make([]string, 0, len(names)-1)
+	whens := strings.TrimSuffix(strings.Repeat("WHEN ? THEN ? ", len(rows)), " ")
+	for j, name := range names[1:] {
+		sets = append(sets, fmt.Sprintf("%s%s%s = CASE %s%s%s %s END", Q, name, Q, Q, pkName, Q, whens))
+		for _, row := range rows {
+			args = append(args, row[0], row[j+1])
+		}
+	}
+	marks := make([]string, len(rows))
+	for i, row := range rows {
+		marks[i] = "?"
+		args = append(args, row[0])
+	}
+	where := fmt.Sprintf("%s%s%s IN (%s)", Q, pkName, Q, strings.Join(marks, ", "))
+	if whereCols, tenantArgs := appendTenantWhere(mi, tenant, nil, nil); len(whereCols) > 0 {
+		where += fmt.Sprintf(" AND %s%s%s = ?", Q, whereCols[0], Q)
+		args = append(args, tenantArgs...)
+	}
+
+	query := ""
+	if mi.schema == "" {
+		query = fmt.Sprintf("UPDATE %s%s%s SET %s WHERE %s", Q, mi.table, Q, strings.Join(sets, ", "), where)
+	} else {
+		query = fmt.Sprintf("UPDATE %s%s%s.%s%s%s SET %s WHERE %s", Q, mi.schema, Q, Q, mi.table, Q, strings.Join(sets, ", "), where)
+	}
+
+	d.ins.ReplaceMarks(&query)
+	return query, args
+}
+
+// get the batched update sql of rows in postgres like databases.
+// UPDATE ... FROM (VALUES ...) is used, values are cast to the column types
+// as VALUES can not infer the types of the parameters.
+func updateMultiValuesSQL(d dbBaser, mi *modelInfo, names []string, rows [][]interface{}, a *alias, tenant interface{}) (string, []interface{}) {
+	Q := d.TableQuote()
+	pkName := names[0]
+
+	casts := make([]string, len(names))
+	for j, name := range names {
+		fi := mi.fields.GetByColumn(name)
+		typ := getColumnTyp(a, fi)
+		if i := strings.Index(typ, " CHECK"); i > 0 {
+			typ = typ[:i]
+		}
+		casts[j] = "CAST(? AS " + typ + ")"
+	}
+	mark := "(" + strings.Join(casts, ", ") + ")"
+
+	args := make([]interface{}, 0, len(rows)*len(names))
+	values := make([]string, len(rows))
+	for i, row := range rows {
+		values[i] = mark
+		args = append(args, row...)
+	}
+	sets := make([]string, 0, len(names)-1)
+	for _, name := range names[1:] {
+		sets = append(sets, fmt.Sprintf("%s%s%s = V.%s%s%s", Q, name, Q, Q, name, Q))
+	}
+	sep := fmt.Sprintf("%s, %s", Q, Q)
+
+	table := Q + mi.table + Q
+	if mi.schema != "" {
+		table = Q + mi.schema + Q + "." + table
+	}
+	query := fmt.Sprintf("UPDATE %s AS T0 SET %s FROM (VALUES %s) AS V (%s%s%s) WHERE T0.%s%s%s = V.%s%s%s",
+		table, strings.Join(sets, ", "), strings.Join(values, ", "), Q, strings.Join(names, sep), Q, Q, pkName, Q, Q, pkName, Q)
+	if whereCols, tenantArgs := appendTenantWhere(mi, tenant, nil, nil); len(whereCols) > 0 {
+		query += fmt.Sprintf(" AND T0.%s%s%s = ?", Q, whereCols[0], Q)
+		args = append(args, tenantArgs...)
+	}
+
+	d.ReplaceMarks(&query)
+	return query, args
+}
+
 // execute insert sql with given struct and given values.
 // insert the given values, not the field values in struct.
 func (d *dbBase) InsertValue(q dbQuerier, mi *modelInfo, isMulti bool, names []string, values []interface{}) (int64, error) {
@@ -626,7 +805,7 @@ func (d *dbBase) InsertOrUpdate(q dbQuerier, mi *modelInfo, ind reflect.Value, a
 }
 
 // execute update sql dbQuerier with given struct reflect.Value.
-func (d *dbBase) Update(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location, cols []string) (int64, error) {
+func (d *dbBase) Update(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location, cols []string, tenant interface{}) (int64, error) {
 	pkName, pkValue, ok := getExistPk(mi, ind)
 	if !ok {
 		return 0, ErrMissPK
@@ -635,8 +814,21 @@ func (d *dbBase) Update(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.
 	var setNames []string
 
 	// if specify cols length is zero, then commit all columns.
+	// fields left unloaded by Only/Defer are kept untouched.
 	if len(cols) == 0 {
 		cols = mi.fields.dbcols
+		if names := getUnloaded(mi, ind); len(names) > 0 {
+			skip := make(map[string]bool, len(names))
+			for _, name := range names {
+				skip[name] = true
+			}
+			cols = make([]string, 0, len(mi.fields.dbcols))
+			for _, fi := range mi.fields.fieldsDB {
+				if !skip[fi.name] || fi.autoNow {
+					cols = append(cols, fi.column)
+				}
+			}
+		}
 		setNames = make([]string, 0, len(mi.fields.dbcols)-1)
 	} else {
 		setNames = make([]string, 0, len(cols))
@@ -647,19 +839,22 @@ func (d *dbBase) Update(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.
 		return 0, err
 	}
 
-	setValues = append(setValues, pkValue)
+	whereCols, setValues := appendTenantWhere(mi, tenant, []string{pkName}, append(setValues, pkValue))
 
 	Q := d.ins.TableQuote()
 
 	sep := fmt.Sprintf("%s = ?, %s", Q, Q)
 	setColumns := strings.Join(setNames, sep)
 
+	sep = fmt.Sprintf("%s = ? AND %s", Q, Q)
+	wheres := strings.Join(whereCols, sep)
+
 	query := ""
 
 	if mi.schema == "" {
-		query = fmt.Sprintf("UPDATE %s%s%s SET %s%s%s = ? WHERE %s%s%s = ?", Q, mi.table, Q, Q, setColumns, Q, Q, pkName, Q)
+		query = fmt.Sprintf("UPDATE %s%s%s SET %s%s%s = ? WHERE %s%s%s = ?", Q, mi.table, Q, Q, setColumns, Q, Q, wheres, Q)
 	} else {
-		query = fmt.Sprintf("UPDATE %s%s%s.%s%s%s SET %s%s%s = ? WHERE %s%s%s = ?", Q, mi.schema, Q, Q, mi.table, Q, Q, setColumns, Q, Q, pkName, Q)
+		query = fmt.Sprintf("UPDATE %s%s%s.%s%s%s SET %s%s%s = ? WHERE %s%s%s = ?", Q, mi.schema, Q, Q, mi.table, Q, Q, setColumns, Q, Q, wheres, Q)
 	}
 
 	d.ins.ReplaceMarks(&query)
@@ -673,7 +868,7 @@ func (d *dbBase) Update(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.
 
 // execute delete sql dbQuerier with given struct reflect.Value.
 // delete index is pk.
-func (d *dbBase) Delete(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location, cols []string) (int64, error) {
+func (d *dbBase) Delete(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.Location, cols []string, tenant interface{}) (int64, error) {
 	var whereCols []string
 	var args []interface{}
 	// if specify cols length > 0, then use it for where condition.
@@ -693,6 +888,7 @@ func (d *dbBase) Delete(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.
 		whereCols = []string{pkColumn}
 		args = append(args, pkValue)
 	}
+	whereCols, whereArgs := appendTenantWhere(mi, tenant, whereCols, args)
 
 	Q := d.ins.TableQuote()
 
@@ -707,7 +903,7 @@ func (d *dbBase) Delete(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.
 	}
 
 	d.ins.ReplaceMarks(&query)
-	res, err := q.Exec(query, args...)
+	res, err := q.Exec(query, whereArgs...)
 	if err == nil {
 		num, err := res.RowsAffected()
 		if err != nil {
@@ -731,9 +927,13 @@ func (d *dbBase) Delete(q dbQuerier, mi *modelInfo, ind reflect.Value, tz *time.
 	return 0, err
 }
 
-// update table-related record by querySet.
-// need querySet not struct reflect.Value to update related records.
-func (d *dbBase) UpdateBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, params Params, tz *time.Location) (int64, error) {
+// get the update sql and args of UpdateBatch without executing it.
+func (d *dbBase) UpdateBatchSQL(qs *querySet, mi *modelInfo, cond *Condition, params Params, tz *time.Location) (string, []interface{}) {
+	return d.updateBatchSQL(qs, mi, cond, params, tz, "")
+}
+
+// build the update sql, output is put between SET and WHERE.
+func (d *dbBase) updateBatchSQL(qs *querySet, mi *modelInfo, cond *Condition, params Params, tz *time.Location, output string) (string, []interface{}) {
 	columns := make([]string, 0, len(params))
 	values := make([]interface{}, 0, len(params))
 	for col, val := range params {
@@ -789,7 +989,7 @@ func (d *dbBase) UpdateBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Con
 		}
 	}
 
-	sets := strings.Join(cols, ", ") + " "
+	sets := strings.Join(cols, ", ") + " " + output
 
 	if d.ins.SupportUpdateJoin() {
 		if mi.schema == "" {
@@ -809,6 +1009,14 @@ func (d *dbBase) UpdateBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Con
 	}
 
 	d.ins.ReplaceMarks(&query)
+	return query, values
+}
+
+// update table-related record by querySet.
+// need querySet not struct reflect.Value to update related records.
+func (d *dbBase) UpdateBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, params Params, tz *time.Location) (int64, error) {
+	query, values := d.UpdateBatchSQL(qs, mi, cond, params, tz)
+
 	var err error
 	var res sql.Result
 	if qs != nil && qs.forContext {
@@ -827,10 +1035,11 @@ func (d *dbBase) UpdateBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Con
 func (d *dbBase) deleteRels(q dbQuerier, mi *modelInfo, args []interface{}, tz *time.Location) error {
 	for _, fi := range mi.fields.fieldsReverse {
 		fi = fi.reverseFieldInfo
+		rmi := mi.sameSchema(fi.mi)
 		switch fi.onDelete {
 		case odCascade:
 			cond := NewCondition().And(fmt.Sprintf("%s__in", fi.name), args...)
-			_, err := d.DeleteBatch(q, nil, fi.mi, cond, tz)
+			_, err := d.DeleteBatch(q, nil, rmi, cond, tz)
 			if err != nil {
 				return err
 			}
@@ -840,7 +1049,7 @@ func (d *dbBase) deleteRels(q dbQuerier, mi *modelInfo, args []interface{}, tz *
 			if fi.onDelete == odSetDefault {
 				params[fi.column] = fi.initial.String()
 			}
-			_, err := d.UpdateBatch(q, nil, fi.mi, cond, params, tz)
+			_, err := d.UpdateBatch(q, nil, rmi, cond, params, tz)
 			if err != nil {
 				return err
 			}
@@ -850,8 +1059,9 @@ func (d *dbBase) deleteRels(q dbQuerier, mi *modelInfo, args []interface{}, tz *
 	return nil
 }
 
-// delete table-related records.
-func (d *dbBase) DeleteBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) (int64, error) {
+// get the sql selecting the pks to delete and its args.
+// DeleteBatch runs it first, then deletes the found pks with IN.
+func (d *dbBase) DeleteBatchSQL(qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) (string, []interface{}) {
 	tables := newDbTables(mi, d.ins)
 	tables.skipEnd = true
 
@@ -879,105 +1089,244 @@ func (d *dbBase) DeleteBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Con
 	}
 
 	d.ins.ReplaceMarks(&query)
+	return query, args
+}
 
-	var rs *sql.Rows
-	r, err := q.Query(query, args...)
-	if err != nil {
+// delete table-related records.
+func (d *dbBase) DeleteBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) (int64, error) {
+	args, err := d.selectPks(q, qs, mi, cond, tz)
+	if err != nil || len(args) == 0 {
 		return 0, err
 	}
-	rs = r
+
+	query := d.deletePksSQL(mi, len(args), "", "")
+	var res sql.Result
+	if qs != nil && qs.forContext {
+		res, err = q.ExecContext(qs.ctx, query, args...)
+	} else {
+		res, err = q.Exec(query, args...)
+	}
+	if err == nil {
+		num, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if num > 0 {
+			err := d.deleteRels(q, mi, args, tz)
+			if err != nil {
+				return num, err
+			}
+		}
+		return num, nil
+	}
+	return 0, err
+}
+
+// select the pks of the rows matching cond.
+func (d *dbBase) selectPks(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) ([]interface{}, error) {
+	query, args := d.DeleteBatchSQL(qs, mi, cond, tz)
+
+	var (
+		rs  *sql.Rows
+		err error
+	)
+	if qs != nil && qs.forContext {
+		rs, err = q.QueryContext(qs.ctx, query, args...)
+	} else {
+		rs, err = q.Query(query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
 	defer rs.Close()
 
 	var ref interface{}
-	args = make([]interface{}, 0)
-	cnt := 0
+	pks := make([]interface{}, 0)
 	for rs.Next() {
 		if err := rs.Scan(&ref); err != nil {
-			return 0, err
+			return nil, err
 		}
 		pkValue, err := d.convertValueFromDB(mi.fields.pk, reflect.ValueOf(ref).Interface(), tz)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
-		args = append(args, pkValue)
-		cnt++
+		pks = append(pks, pkValue)
 	}
+	return pks, rs.Err()
+}
 
-	if cnt == 0 {
-		return 0, nil
-	}
+// get the sql deleting num pks with IN, output goes before WHERE and returning ends the query.
+func (d *dbBase) deletePksSQL(mi *modelInfo, num int, output, returning string) string {
+	Q := d.ins.TableQuote()
 
-	marks := make([]string, len(args))
+	marks := make([]string, num)
 	for i := range marks {
 		marks[i] = "?"
 	}
 	sqlIn := fmt.Sprintf("IN (%s)", strings.Join(marks, ", "))
 
+	query := ""
 	if mi.schema == "" {
-		query = fmt.Sprintf("DELETE FROM %s%s%s WHERE %s%s%s %s", Q, mi.table, Q, Q, mi.fields.pk.column, Q, sqlIn)
+		query = fmt.Sprintf("DELETE FROM %s%s%s %sWHERE %s%s%s %s%s", Q, mi.table, Q, output, Q, mi.fields.pk.column, Q, sqlIn, returning)
 	} else {
-		query = fmt.Sprintf("DELETE FROM %s%s%s.%s%s%s WHERE %s%s%s %s", Q, mi.schema, Q, Q, mi.table, Q, Q, mi.fields.pk.column, Q, sqlIn)
+		query = fmt.Sprintf("DELETE FROM %s%s%s.%s%s%s %sWHERE %s%s%s %s%s", Q, mi.schema, Q, Q, mi.table, Q, output, Q, mi.fields.pk.column, Q, sqlIn, returning)
 	}
 
 	d.ins.ReplaceMarks(&query)
-	var res sql.Result
-	if qs != nil && qs.forContext {
-		res, err = q.ExecContext(qs.ctx, query, args...)
-	} else {
-		res, err = q.Exec(query, args...)
+	return query
+}
+
+// get the clauses returning the modified rows of mi.
+// output goes before WHERE (sqlserver OUTPUT), returning ends the query (RETURNING).
+// both are empty if the database can not return rows, the statements are emulated then.
+func (d *dbBase) ReturningSQL(mi *modelInfo, deleted bool) (output string, returning string) {
+	return "", ""
+}
+
+// RETURNING of postgres like databases and sqlite 3.35+.
+func returningSQL(d dbBaser, mi *modelInfo) string {
+	Q := d.TableQuote()
+	return fmt.Sprintf(" RETURNING %s%s%s", Q, strings.Join(mi.fields.dbcols, fmt.Sprintf("%s, %s", Q, Q)), Q)
+}
+
+// get the CREATE SCHEMA IF NOT EXISTS sql of the postgresql family.
+func createSchemaSQL(d dbBaser, schema string) string {
+	Q := d.TableQuote()
+	return fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s%s%s", Q, schema, Q)
+}
+
+// update the rows matching cond and scan the updated rows into container.
+func (d *dbBase) UpdateBatchReturning(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, params Params, container interface{}, tz *time.Location) (int64, error) {
+	output, returning := d.ins.ReturningSQL(mi, false)
+	if output == "" && returning == "" {
+		var cnt int64
+		err := withTx(q, func(q dbQuerier) error {
+			pks, err := d.selectPks(q, qs, mi, cond, tz)
+			if err != nil || len(pks) == 0 {
+				return err
+			}
+			pkCond := NewCondition().And(mi.fields.pk.name+ExprSep+"in", pks)
+			if _, err = d.ins.UpdateBatch(q, nil, mi, pkCond, params, tz); err != nil {
+				return err
+			}
+			cnt, err = d.ins.ReadBatch(q, returningQs(qs, mi), mi, pkCond, container, tz, nil)
+			return err
+		})
+		return cnt, err
 	}
-	if err == nil {
-		num, err := res.RowsAffected()
-		if err != nil {
-			return 0, err
+
+	query, values := d.updateBatchSQL(qs, mi, cond, params, tz, output)
+	query += returning
+	return d.queryReturning(q, qs, mi, query, values, container, tz)
+}
+
+// delete the rows matching cond and scan the deleted rows into container.
+func (d *dbBase) DeleteBatchReturning(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, container interface{}, tz *time.Location) (int64, error) {
+	output, returning := d.ins.ReturningSQL(mi, true)
+	var cnt int64
+	err := withTx(q, func(q dbQuerier) error {
+		pks, err := d.selectPks(q, qs, mi, cond, tz)
+		if err != nil || len(pks) == 0 {
+			return err
 		}
-		if num > 0 {
-			err := d.deleteRels(q, mi, args, tz)
-			if err != nil {
-				return num, err
+		if output == "" && returning == "" {
+			pkCond := NewCondition().And(mi.fields.pk.name+ExprSep+"in", pks)
+			if cnt, err = d.ins.ReadBatch(q, returningQs(qs, mi), mi, pkCond, container, tz, nil); err != nil {
+				return err
 			}
+			_, err = d.ins.DeleteBatch(q, nil, mi, pkCond, tz)
+			return err
 		}
-		return num, nil
-	}
-	return 0, err
+		query := d.deletePksSQL(mi, len(pks), output, returning)
+		if cnt, err = d.queryReturning(q, qs, mi, query, pks, container, tz); err != nil {
+			return err
+		}
+		return d.deleteRels(q, mi, pks, tz)
+	})
+	return cnt, err
 }
 
-// read related records.
-func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, container interface{}, tz *time.Location, cols []string) (int64, error) {
-
+// run a query returning full rows of mi and scan them into container.
+func (d *dbBase) queryReturning(q dbQuerier, qs *querySet, mi *modelInfo, query string, args []interface{}, container interface{}, tz *time.Location) (int64, error) {
 	val := reflect.ValueOf(container)
 	ind := reflect.Indirect(val)
+	if val.Kind() != reflect.Ptr || ind.Kind() != reflect.Slice {
+		panic(fmt.Errorf("wrong object type `%s` for rows scan, need *[]*%s or *[]%s", val.Type(), mi.fullName, mi.fullName))
+	}
+	typ := ind.Type().Elem()
+	isPtr := typ.Kind() == reflect.Ptr
+	if isPtr {
+		typ = typ.Elem()
+	}
+	if getFullName(typ) != mi.fullName {
+		panic(fmt.Errorf("wrong object type `%s` for rows scan, need *[]*%s or *[]%s", val.Type(), mi.fullName, mi.fullName))
+	}
 
-	errTyp := true
-	one := true
-	isPtr := true
+	var (
+		rs  *sql.Rows
+		err error
+	)
+	if qs != nil && qs.forContext {
+		rs, err = q.QueryContext(qs.ctx, query, args...)
+	} else {
+		rs, err = q.Query(query, args...)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer rs.Close()
 
-	if val.Kind() == reflect.Ptr {
-		fn := ""
-		if ind.Kind() == reflect.Slice {
-			one = false
-			typ := ind.Type().Elem()
-			switch typ.Kind() {
-			case reflect.Ptr:
-				fn = getFullName(typ.Elem())
-			case reflect.Struct:
-				isPtr = false
-				fn = getFullName(typ)
-			}
+	refs := make([]interface{}, len(mi.fields.dbcols))
+	for i := range refs {
+		var ref interface{}
+		refs[i] = &ref
+	}
+	slice := reflect.MakeSlice(ind.Type(), 0, 0)
+	var cnt int64
+	for rs.Next() {
+		if err := rs.Scan(refs...); err != nil {
+			return cnt, err
+		}
+		elm := reflect.New(typ)
+		mind := reflect.Indirect(elm)
+		d.setColsValues(mi, &mind, mi.fields.dbcols, refs, tz)
+		if isPtr {
+			slice = reflect.Append(slice, elm)
 		} else {
-			fn = getFullName(ind.Type())
+			slice = reflect.Append(slice, mind)
 		}
-		errTyp = fn != mi.fullName
+		cnt++
 	}
+	if err := rs.Err(); err != nil {
+		return cnt, err
+	}
+	ind.Set(slice)
+	return cnt, nil
+}
 
-	if errTyp {
-		if one {
-			panic(fmt.Errorf("wrong object type `%s` for rows scan, need *%s", val.Type(), mi.fullName))
-		} else {
-			panic(fmt.Errorf("wrong object type `%s` for rows scan, need *[]*%s or *[]%s", val.Type(), mi.fullName, mi.fullName))
-		}
+// querySet reading all rows by pk when emulating RETURNING.
+func returningQs(qs *querySet, mi *modelInfo) *querySet {
+	rqs := &querySet{mi: mi, limit: -1, orders: []string{mi.fields.pk.name}}
+	if qs != nil {
+		rqs.ctx, rqs.forContext = qs.ctx, qs.forContext
 	}
+	return rqs
+}
+
+// sql and column layout of a ReadBatch query.
+type readBatchQuery struct {
+	query       string
+	args        []interface{}
+	tables      *dbTables
+	tCols       []string
+	unloaded    []string
+	tblCols     map[*dbTable][]string
+	tblUnloaded map[*dbTable][]string
+	colsNum     int
+}
 
+// build the select sql of ReadBatch.
+func (d *dbBase) readBatchQuery(qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location, cols []string) (*readBatchQuery, error) {
 	rlimit := qs.limit
 	offset := qs.offset
 
@@ -998,7 +1347,7 @@ func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condi
 					maps[fi.column] = true
 				}
 			} else {
-				return 0, fmt.Errorf("wrong field/column name `%s`", col)
+				return nil, fmt.Errorf("wrong field/column name `%s`", col)
 			}
 		}
 		if hasRel {
@@ -1014,24 +1363,45 @@ func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condi
 		tCols = mi.fields.dbcols
 	}
 
+	// Only/Defer shape the columns of every selected table
+	var (
+		onlys, defers map[string]map[string]bool
+		unloaded      []string
+		err           error
+	)
+	if len(qs.only) > 0 || len(qs.defers) > 0 {
+		if onlys, err = getLoadFields(mi, qs.only, false); err != nil {
+			return nil, err
+		}
+		if defers, err = getLoadFields(mi, qs.defers, true); err != nil {
+			return nil, err
+		}
+		if len(cols) == 0 {
+			tCols, _, unloaded = getLoadColumns(mi, onlys[""], defers[""])
+		}
+	}
+
 	colsNum := len(tCols)
 	sep := fmt.Sprintf("%s, T0.%s", Q, Q)
 	sels := fmt.Sprintf("T0.%s%s%s", Q, strings.Join(tCols, sep), Q)
 
 	tables := newDbTables(mi, d.ins)
 	tables.parseRelated(qs.related, qs.relDepth)
+	tblCols := make(map[*dbTable][]string, len(tables.tables))
+	tblUnloaded := make(map[*dbTable][]string, len(tables.tables))
 
 	where, args := tables.getCondSQL(cond, false, tz)
 	groupBy := tables.getGroupSQL(qs.groups)
-	orderBy := tables.getOrderSQL(qs.orders)
+	orderBy := tables.getOrderSQL(qs.getOrders())
 	limit := tables.getLimitSQL(mi, offset, rlimit)
 	join := tables.getJoinSQL()
 
 	for _, tbl := range tables.tables {
 		if tbl.sel {
-			colsNum += len(tbl.mi.fields.dbcols)
+			tblCols[tbl], _, tblUnloaded[tbl] = getLoadColumns(tbl.mi, onlys[tbl.name], defers[tbl.name])
+			colsNum += len(tblCols[tbl])
 			sep := fmt.Sprintf("%s, %s.%s", Q, tbl.index, Q)
-			sels += fmt.Sprintf(", %s.%s%s%s", tbl.index, Q, strings.Join(tbl.mi.fields.dbcols, sep), Q)
+			sels += fmt.Sprintf(", %s.%s%s%s", tbl.index, Q, strings.Join(tblCols[tbl], sep), Q)
 		}
 	}
 
@@ -1040,21 +1410,96 @@ func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condi
 		sqlSelect += " DISTINCT"
 	}
 
+	hint, forUpdate := "", ""
+	if qs.lock != nil {
+		of, err := tables.getLockTables(qs.lock.Of)
+		if err != nil {
+			return nil, err
+		}
+		if hint, forUpdate, err = d.ins.RowLockSQL(qs.lock, of); err != nil {
+			return nil, err
+		}
+	}
+
 	query := ""
 	if mi.schema == "" {
-		query = fmt.Sprintf("%s %s FROM %s%s%s T0 %s%s%s%s%s", sqlSelect, sels, Q, mi.table, Q, join, where, groupBy, orderBy, limit)
+		query = fmt.Sprintf("%s %s FROM %s%s%s T0%s %s%s%s%s%s", sqlSelect, sels, Q, mi.table, Q, hint, join, where, groupBy, orderBy, limit)
 	} else {
-		query = fmt.Sprintf("%s %s FROM %s%s%s.%s%s%s T0 %s%s%s%s%s", sqlSelect, sels, Q, mi.schema, Q, Q, mi.table, Q, join, where, groupBy, orderBy, limit)
+		query = fmt.Sprintf("%s %s FROM %s%s%s.%s%s%s T0%s %s%s%s%s%s", sqlSelect, sels, Q, mi.schema, Q, Q, mi.table, Q, hint, join, where, groupBy, orderBy, limit)
 	}
 
-	if qs.forupdate {
-		query += " FOR UPDATE"
+	if forUpdate != "" {
+		query += " " + forUpdate
 	}
 
 	d.ins.ReplaceMarks(&query)
 
+	return &readBatchQuery{
+		query:       query,
+		args:        args,
+		tables:      tables,
+		tCols:       tCols,
+		unloaded:    unloaded,
+		tblCols:     tblCols,
+		tblUnloaded: tblUnloaded,
+		colsNum:     colsNum,
+	}, nil
+}
+
+// get the select sql and args of ReadBatch without executing it.
+func (d *dbBase) ReadBatchSQL(qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location, cols []string) (string, []interface{}, error) {
+	rq, err := d.readBatchQuery(qs, mi, cond, tz, cols)
+	if err != nil {
+		return "", nil, err
+	}
+	return rq.query, rq.args, nil
+}
+
+// read related records.
+func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, container interface{}, tz *time.Location, cols []string) (int64, error) {
+
+	val := reflect.ValueOf(container)
+	ind := reflect.Indirect(val)
+
+	errTyp := true
+	one := true
+	isPtr := true
+
+	if val.Kind() == reflect.Ptr {
+		fn := ""
+		if ind.Kind() == reflect.Slice {
+			one = false
+			typ := ind.Type().Elem()
+			switch typ.Kind() {
+			case reflect.Ptr:
+				fn = getFullName(typ.Elem())
+			case reflect.Struct:
+				isPtr = false
+				fn = getFullName(typ)
+			}
+		} else {
+			fn = getFullName(ind.Type())
+		}
+		errTyp = fn != mi.fullName
+	}
+
+	if errTyp {
+		if one {
+			panic(fmt.Errorf("wrong object type `%s` for rows scan, need *%s", val.Type(), mi.fullName))
+		} else {
+			panic(fmt.Errorf("wrong object type `%s` for rows scan, need *[]*%s or *[]%s", val.Type(), mi.fullName, mi.fullName))
+		}
+	}
+
+	rq, err := d.readBatchQuery(qs, mi, cond, tz, cols)
+	if err != nil {
+		return 0, err
+	}
+	tables, tCols, unloaded := rq.tables, rq.tCols, rq.unloaded
+	tblCols, tblUnloaded := rq.tblCols, rq.tblUnloaded
+	query, args, colsNum := rq.query, rq.args, rq.colsNum
+
 	var rs *sql.Rows
-	var err error
 	if qs != nil && qs.forContext {
 		rs, err = q.QueryContext(qs.ctx, query, args...)
 		if err != nil {
@@ -1092,6 +1537,8 @@ func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condi
 			trefs := refs
 
 			d.setColsValues(mi, &mind, tCols, refs[:len(tCols)], tz)
+			// the marks are copied with the model into the container
+			setUnloaded(mi, mind, unloaded)
 			trefs = refs[len(tCols):]
 
 			for _, tbl := range tables.tables {
@@ -1114,9 +1561,11 @@ func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condi
 							if last.Kind() != reflect.Invalid {
 								field = reflect.Indirect(last.FieldByIndex(fi.fieldIndex))
 								if field.IsValid() {
-									d.setColsValues(mmi, &field, mmi.fields.dbcols, trefs[:len(mmi.fields.dbcols)], tz)
+									d.setColsValues(mmi, &field, tblCols[tbl], trefs[:len(tblCols[tbl])], tz)
+									setUnloaded(mmi, field, tblUnloaded[tbl])
 									for _, fi := range mmi.fields.fieldsReverse {
-										if fi.inModel && fi.reverseFieldInfo.mi == lastm {
+										// lastm may be a UsingSchema copy of the registered model
+										if fi.inModel && fi.reverseFieldInfo.mi.fullName == lastm.fullName {
 											if fi.reverseFieldInfo != nil {
 												f := field.FieldByIndex(fi.fieldIndex)
 												if f.Kind() == reflect.Ptr {
@@ -1132,7 +1581,7 @@ func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condi
 							cacheM[names] = mmi
 						}
 					}
-					trefs = trefs[len(mmi.fields.dbcols):]
+					trefs = trefs[len(tblCols[tbl]):]
 				}
 			}
 
@@ -1174,14 +1623,14 @@ func (d *dbBase) ReadBatch(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condi
 	return cnt, nil
 }
 
-// excute count sql and return count result int64.
-func (d *dbBase) Count(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) (cnt int64, err error) {
+// get the count sql and args without executing it.
+func (d *dbBase) CountSQL(qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) (string, []interface{}) {
 	tables := newDbTables(mi, d.ins)
 	tables.parseRelated(qs.related, qs.relDepth)
 
 	where, args := tables.getCondSQL(cond, false, tz)
 	groupBy := tables.getGroupSQL(qs.groups)
-	tables.getOrderSQL(qs.orders)
+	tables.getOrderSQL(qs.getOrders())
 	join := tables.getJoinSQL()
 
 	Q := d.ins.TableQuote()
@@ -1198,6 +1647,12 @@ func (d *dbBase) Count(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition
 	}
 
 	d.ins.ReplaceMarks(&query)
+	return query, args
+}
+
+// excute count sql and return count result int64.
+func (d *dbBase) Count(q dbQuerier, qs *querySet, mi *modelInfo, cond *Condition, tz *time.Location) (cnt int64, err error) {
+	query, args := d.CountSQL(qs, mi, cond, tz)
 
 	var row *sql.Row
 	if qs != nil && qs.forContext {
@@ -1462,6 +1917,16 @@ func (d *dbBase) setFieldValue(fi *fieldInfo, value interface{}, field reflect.V
 
 setValue:
 	switch {
+	case fi.toJSON:
+		if s, ok := value.(string); value == nil || (ok && s == "") {
+			field.Set(reflect.Zero(field.Type()))
+		} else {
+			v := reflect.New(field.Type())
+			if err := json.Unmarshal([]byte(ToStr(value)), v.Interface()); err != nil {
+				return nil, fmt.Errorf("field `%s` json unmarshal failed, %s", fi.fullName, err)
+			}
+			field.Set(v.Elem())
+		}
 	case fieldType == TypeBooleanField:
 		if isNative {
 			if nb, ok := field.Interface().(sql.NullBool); ok {
@@ -1712,7 +2177,7 @@ func (d *dbBase) ReadValues(q dbQuerier, qs *querySet, mi *modelInfo, cond *Cond
 
 	where, args := tables.getCondSQL(cond, false, tz)
 	groupBy := tables.getGroupSQL(qs.groups)
-	orderBy := tables.getOrderSQL(qs.orders)
+	orderBy := tables.getOrderSQL(qs.getOrders())
 	limit := tables.getLimitSQL(mi, qs.offset, qs.limit)
 	join := tables.getJoinSQL()
 
@@ -1900,6 +2365,57 @@ func (d *dbBase) GetTables(db dbQuerier) (map[string]bool, error) {
 	return tables, nil
 }
 
+// get all tables in schema.
+func (d *dbBase) GetSchemaTables(db dbQuerier, schema string) (map[string]bool, error) {
+	tables := make(map[string]bool)
+	query := "SELECT table_name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_schema = ?"
+	d.ins.ReplaceMarks(&query)
+	rows, err := db.Query(query, schema)
+	if err != nil {
+		return tables, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return tables, err
+		}
+		tables[table] = true
+	}
+
+	return tables, rows.Err()
+}
+
+// get all cloumns in table of schema.
+func (d *dbBase) GetSchemaColumns(db dbQuerier, schema, table string) (map[string][3]string, error) {
+	columns := make(map[string][3]string)
+	query := "SELECT column_name, data_type, is_nullable FROM information_schema.columns WHERE table_schema = ? AND table_name = ?"
+	d.ins.ReplaceMarks(&query)
+	rows, err := db.Query(query, schema, table)
+	if err != nil {
+		return columns, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, typ, null string
+		if err := rows.Scan(&name, &typ, &null); err != nil {
+			return columns, err
+		}
+		columns[name] = [3]string{name, typ, null}
+	}
+
+	return columns, rows.Err()
+}
+
+// get the sql creating schema, only drivers with schemas in a database support it.
+func (d *dbBase) CreateSchemaSQL(schema string) (string, error) {
+	return "", fmt.Errorf("<syncdb> schema `%s` is not supported by the driver", schema)
+}
+
 // get all cloumns in table.
 func (d *dbBase) GetColumns(db dbQuerier, table string) (map[string][3]string, error) {
 	columns := make(map[string][3]string)
@@ -1946,3 +2462,36 @@ func (d *dbBase) ShowColumnsQuery(table string) string {
 func (d *dbBase) IndexExists(dbQuerier, string, string) bool {
 	panic(ErrNotImplement)
 }
+
+// run EXPLAIN on query and return the plan rows.
+func (d *dbBase) Explain(q dbQuerier, query string, args []interface{}, analyze bool) ([]Params, error) {
+	prefix := "EXPLAIN "
+	if analyze {
+		prefix = "EXPLAIN ANALYZE "
+	}
+	return readPlanRows(q.Query(prefix+query, args...))
+}
+
+// get the row lock sql of a SELECT.
+// hint follows the table name with a leading space, suffix ends the query.
+// of holds the tables resolved from lock.Of.
+func (d *dbBase) RowLockSQL(lock *RowLock, of []*dbTable) (hint string, suffix string, err error) {
+	suffix = "FOR UPDATE"
+	if lock.Share {
+		suffix = "FOR SHARE"
+	}
+	if len(of) > 0 {
+		names := make([]string, 0, len(of))
+		for _, tbl := range of {
+			names = append(names, tbl.index)
+		}
+		suffix += " OF " + strings.Join(names, ", ")
+	}
+	switch lock.Wait {
+	case NoWait:
+		suffix += " NOWAIT"
+	case SkipLocked:
+		suffix += " SKIP LOCKED"
+	}
+	return
+}
@@ -243,3 +243,8 @@ func (d *dbBaseDm) InsertValue(q dbQuerier, mi *modelInfo, isMulti bool, names [
 	err := row.Scan(&id)
 	return id, err
 }
+
+// get the row lock sql in dm, the same as oracle.
+func (d *dbBaseDm) RowLockSQL(lock *RowLock, of []*dbTable) (string, string, error) {
+	return oracleRowLockSQL("dm", lock, of)
+}
@@ -252,3 +252,18 @@ func newdbBaseGpdb() dbBaser {
 	b.ins = b
 	return b
 }
+
+// get the batched update sql by UPDATE ... FROM (VALUES ...).
+func (d *dbBaseGpdb) UpdateMultiSQL(mi *modelInfo, names []string, rows [][]interface{}, a *alias, tenant interface{}) (string, []interface{}) {
+	return updateMultiValuesSQL(d, mi, names, rows, a, tenant)
+}
+
+// greenplum returns the modified rows by RETURNING.
+func (d *dbBaseGpdb) ReturningSQL(mi *modelInfo, deleted bool) (string, string) {
+	return "", returningSQL(d, mi)
+}
+
+// greenplum creates the schema of a UsingSchema Ormer by CREATE SCHEMA IF NOT EXISTS.
+func (d *dbBaseGpdb) CreateSchemaSQL(schema string) (string, error) {
+	return createSchemaSQL(d, schema), nil
+}
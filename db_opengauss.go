@@ -176,3 +176,18 @@ func newdbBaseOpengauss() dbBaser {
 	b.ins = b
 	return b
 }
+
+// get the batched update sql by UPDATE ... FROM (VALUES ...).
+func (d *dbBaseOpengauss) UpdateMultiSQL(mi *modelInfo, names []string, rows [][]interface{}, a *alias, tenant interface{}) (string, []interface{}) {
+	return updateMultiValuesSQL(d, mi, names, rows, a, tenant)
+}
+
+// opengauss returns the modified rows by RETURNING.
+func (d *dbBaseOpengauss) ReturningSQL(mi *modelInfo, deleted bool) (string, string) {
+	return "", returningSQL(d, mi)
+}
+
+// opengauss creates the schema of a UsingSchema Ormer by CREATE SCHEMA IF NOT EXISTS.
+func (d *dbBaseOpengauss) CreateSchemaSQL(schema string) (string, error) {
+	return createSchemaSQL(d, schema), nil
+}
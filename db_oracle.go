@@ -243,3 +243,50 @@ func (d *dbBaseOracle) InsertValue(q dbQuerier, mi *modelInfo, isMulti bool, nam
 	err := row.Scan(&id)
 	return id, err
 }
+
+// run EXPLAIN PLAN FOR and read the plan with DBMS_XPLAN.DISPLAY in oracle.
+// both statements must run in the same session, so a transaction is used on *sql.DB.
+func (d *dbBaseOracle) Explain(q dbQuerier, query string, args []interface{}, analyze bool) ([]Params, error) {
+	if analyze {
+		return nil, fmt.Errorf("<QuerySeter.Explain> oracle does not support EXPLAIN ANALYZE")
+	}
+	if db, ok := q.(txer); ok {
+		tx, err := db.Begin()
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+		q = tx
+	}
+	if _, err := q.Exec("EXPLAIN PLAN FOR "+query, args...); err != nil {
+		return nil, err
+	}
+	return readPlanRows(q.Query("SELECT PLAN_TABLE_OUTPUT FROM TABLE(DBMS_XPLAN.DISPLAY())"))
+}
+
+// get the row lock sql in oracle, FOR UPDATE OF takes columns and FOR SHARE is not supported.
+func (d *dbBaseOracle) RowLockSQL(lock *RowLock, of []*dbTable) (string, string, error) {
+	return oracleRowLockSQL("oracle", lock, of)
+}
+
+// FOR UPDATE [OF T0.pk] [NOWAIT | SKIP LOCKED] of oracle and compatible databases.
+func oracleRowLockSQL(driver string, lock *RowLock, of []*dbTable) (string, string, error) {
+	if lock.Share {
+		return "", "", fmt.Errorf("<QuerySeter.ForShare> %s does not support FOR SHARE", driver)
+	}
+	suffix := "FOR UPDATE"
+	if len(of) > 0 {
+		cols := make([]string, 0, len(of))
+		for _, tbl := range of {
+			cols = append(cols, tbl.index+"."+tbl.mi.fields.pk.column)
+		}
+		suffix += " OF " + strings.Join(cols, ", ")
+	}
+	switch lock.Wait {
+	case NoWait:
+		suffix += " NOWAIT"
+	case SkipLocked:
+		suffix += " SKIP LOCKED"
+	}
+	return "", suffix, nil
+}
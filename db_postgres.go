@@ -190,3 +190,18 @@ func newdbBasePostgres() dbBaser {
 	b.ins = b
 	return b
 }
+
+// get the batched update sql by UPDATE ... FROM (VALUES ...).
+func (d *dbBasePostgres) UpdateMultiSQL(mi *modelInfo, names []string, rows [][]interface{}, a *alias, tenant interface{}) (string, []interface{}) {
+	return updateMultiValuesSQL(d, mi, names, rows, a, tenant)
+}
+
+// postgres returns the modified rows by RETURNING.
+func (d *dbBasePostgres) ReturningSQL(mi *modelInfo, deleted bool) (string, string) {
+	return "", returningSQL(d, mi)
+}
+
+// postgres creates the schema of a UsingSchema Ormer by CREATE SCHEMA IF NOT EXISTS.
+func (d *dbBasePostgres) CreateSchemaSQL(schema string) (string, error) {
+	return createSchemaSQL(d, schema), nil
+}
@@ -149,3 +149,21 @@ func newdbBaseSqlite() dbBaser {
 	b.ins = b
 	return b
 }
+
+// run EXPLAIN QUERY PLAN in sqlite, analyze is not supported.
+func (d *dbBaseSqlite) Explain(q dbQuerier, query string, args []interface{}, analyze bool) ([]Params, error) {
+	if analyze {
+		return nil, fmt.Errorf("<QuerySeter.Explain> sqlite3 does not support EXPLAIN ANALYZE")
+	}
+	return readPlanRows(q.Query("EXPLAIN QUERY PLAN "+query, args...))
+}
+
+// sqlite locks the whole database, row locks are not supported.
+func (d *dbBaseSqlite) RowLockSQL(lock *RowLock, of []*dbTable) (string, string, error) {
+	return "", "", fmt.Errorf("<QuerySeter.ForUpdate> sqlite3 does not support row locks")
+}
+
+// sqlite 3.35+ returns the modified rows by RETURNING.
+func (d *dbBaseSqlite) ReturningSQL(mi *modelInfo, deleted bool) (string, string) {
+	return "", returningSQL(d, mi)
+}
@@ -113,3 +113,49 @@ func (d *dbBaseSqlserver) ReplaceMarks(query *string) {
 		*query = *query + ss[len(ss)-1]
 	}
 }
+
+// sqlserver shows plans by SET SHOWPLAN_ALL on a dedicated connection, not supported.
+func (d *dbBaseSqlserver) Explain(q dbQuerier, query string, args []interface{}, analyze bool) ([]Params, error) {
+	return nil, fmt.Errorf("<QuerySeter.Explain> sqlserver does not support EXPLAIN, use SET SHOWPLAN_ALL instead")
+}
+
+// sqlserver locks rows by table hints after the main table, there is no FOR UPDATE.
+func (d *dbBaseSqlserver) RowLockSQL(lock *RowLock, of []*dbTable) (string, string, error) {
+	for _, tbl := range of {
+		if tbl.index != "T0" {
+			return "", "", fmt.Errorf("<QuerySeter.ForUpdateOf> sqlserver can only lock the main table, `%s` is joined", tbl.name)
+		}
+	}
+	hints := []string{"UPDLOCK", "ROWLOCK"}
+	if lock.Share {
+		hints = []string{"HOLDLOCK", "ROWLOCK"}
+	}
+	switch lock.Wait {
+	case NoWait:
+		hints = append(hints, "NOWAIT")
+	case SkipLocked:
+		hints = append(hints, "READPAST")
+	}
+	return " WITH (" + strings.Join(hints, ", ") + ")", "", nil
+}
+
+// sqlserver returns the modified rows by OUTPUT INSERTED.* or DELETED.*.
+func (d *dbBaseSqlserver) ReturningSQL(mi *modelInfo, deleted bool) (string, string) {
+	prefix := "INSERTED."
+	if deleted {
+		prefix = "DELETED."
+	}
+	Q := d.TableQuote()
+	cols := make([]string, len(mi.fields.dbcols))
+	for i, col := range mi.fields.dbcols {
+		cols[i] = prefix + Q + col + Q
+	}
+	return "OUTPUT " + strings.Join(cols, ", ") + " ", ""
+}
+
+// sqlserver has no CREATE SCHEMA IF NOT EXISTS, the schema is created in EXEC when missing.
+func (d *dbBaseSqlserver) CreateSchemaSQL(schema string) (string, error) {
+	name := strings.Replace(schema, "'", "''", -1)
+	quoted := strings.Replace(strings.Replace(schema, "]", "]]", -1), "'", "''", -1)
+	return fmt.Sprintf("IF NOT EXISTS (SELECT * FROM sys.schemas WHERE name = N'%s') EXEC('CREATE SCHEMA [%s]')", name, quoted), nil
+}
@@ -184,6 +184,10 @@ func (t *dbTables) getJoinSQL() (join string) {
 		}
 		t2 = jt.index
 		table = jt.mi.table
+		if t.mi.origin != nil {
+			// joined tables live in the schema of the UsingSchema Ormer
+			table = t.mi.schema + Q + "." + Q + table
+		}
 
 		switch {
 		case jt.fi.fieldType == RelManyToMany || jt.fi.fieldType == RelReverseMany || jt.fi.reverse && jt.fi.reverseFieldInfo.fieldType == RelManyToMany:
@@ -472,6 +476,32 @@ func (t *dbTables) getLimitSQL(mi *modelInfo, offset int64, limit int64) (limits
 	return
 }
 
+// get the tables locked by FOR UPDATE OF.
+// name is the model table name or the related name of a joined table.
+func (t *dbTables) getLockTables(names []string) ([]*dbTable, error) {
+	of := make([]*dbTable, 0, len(names))
+	for _, name := range names {
+		if name == t.mi.table {
+			of = append(of, &dbTable{index: "T0", mi: t.mi})
+			continue
+		}
+		jt, ok := t.get(name)
+		if !ok {
+			for _, tbl := range t.tables {
+				if tbl.mi.table == name {
+					jt, ok = tbl, true
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("<QuerySeter.ForUpdateOf> table `%s` is not in the query of `%s`", name, t.mi.fullName)
+		}
+		of = append(of, jt)
+	}
+	return of, nil
+}
+
 // crete new tables collection.
 func newDbTables(mi *modelInfo, base dbBaser) *dbTables {
 	tables := &dbTables{}
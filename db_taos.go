@@ -126,3 +126,8 @@ func newdbBaseTaos() dbBaser {
 	b.ins = b
 	return b
 }
+
+// tdengine has no transactions, row locks are not supported.
+func (d *dbBaseTaos) RowLockSQL(lock *RowLock, of []*dbTable) (string, string, error) {
+	return "", "", fmt.Errorf("<QuerySeter.ForUpdate> tdengine does not support row locks")
+}
@@ -61,3 +61,14 @@ func newdbBaseTidb() dbBaser {
 	b.ins = b
 	return b
 }
+
+// tidb supports FOR UPDATE [OF] [NOWAIT] only.
+func (d *dbBaseTidb) RowLockSQL(lock *RowLock, of []*dbTable) (string, string, error) {
+	if lock.Share {
+		return "", "", fmt.Errorf("<QuerySeter.ForShare> tidb does not support FOR SHARE")
+	}
+	if lock.Wait == SkipLocked {
+		return "", "", fmt.Errorf("<QuerySeter.ForUpdate> tidb does not support SKIP LOCKED")
+	}
+	return d.dbBase.RowLockSQL(lock, of)
+}
@@ -15,8 +15,11 @@
 package orm
 
 import (
+	"database/sql"
+	sqldriver "database/sql/driver"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -65,7 +68,8 @@ outFor:
 	for _, arg := range args {
 		val := reflect.ValueOf(arg)
 
-		if arg == nil {
+		// a driver.Valuer is converted by database/sql, slices too
+		if _, ok := arg.(sqldriver.Valuer); ok || arg == nil {
 			params = append(params, arg)
 			continue
 		}
@@ -124,7 +128,7 @@ outFor:
 			arg = val.Bool()
 		case reflect.Slice, reflect.Array:
 			if _, ok := arg.([]byte); ok {
-				continue outFor
+				break
 			}
 
 			var args []interface{}
@@ -180,3 +184,156 @@ outFor:
 	}
 	return
 }
+
+// parse Only/Defer expressions into field names grouped by related path.
+// "UserName" belongs to the root path "", "User__Profile__Age" to "User__Profile".
+func getLoadFields(mi *modelInfo, exprs []string, isDefer bool) (map[string]map[string]bool, error) {
+	loads := make(map[string]map[string]bool)
+	for _, expr := range exprs {
+		exs := strings.Split(expr, ExprSep)
+		names := make([]string, 0, len(exs)-1)
+		mmi := mi
+		for _, ex := range exs[:len(exs)-1] {
+			fi, ok := mmi.fields.GetByAny(ex)
+			if !ok || !fi.rel || fi.fieldType == RelManyToMany {
+				return nil, fmt.Errorf("unknown relation `%s` in `%s`", ex, expr)
+			}
+			names = append(names, fi.name)
+			mmi = fi.relModelInfo
+		}
+		fi, ok := mmi.fields.GetByAny(exs[len(exs)-1])
+		if !ok || !fi.dbcol {
+			return nil, fmt.Errorf("wrong field/column name `%s`", expr)
+		}
+		if isDefer && (fi.pk || fi.rel) {
+			return nil, fmt.Errorf("primary key or relation field `%s` can not be deferred", expr)
+		}
+		path := strings.Join(names, ExprSep)
+		if loads[path] == nil {
+			loads[path] = make(map[string]bool)
+		}
+		loads[path][fi.name] = true
+	}
+	return loads, nil
+}
+
+// get the columns to select by Only/Defer field names, the loaded and the unloaded field names.
+// pk and relation columns are always loaded.
+func getLoadColumns(mi *modelInfo, only, defers map[string]bool) (cols []string, names []string, unloaded []string) {
+	cols = make([]string, 0, len(mi.fields.fieldsDB))
+	for _, fi := range mi.fields.fieldsDB {
+		load := true
+		if len(only) > 0 {
+			load = fi.pk || fi.rel || only[fi.name]
+		} else if defers[fi.name] {
+			load = false
+		}
+		if load {
+			cols = append(cols, fi.column)
+			names = append(names, fi.name)
+		} else {
+			unloaded = append(unloaded, fi.name)
+		}
+	}
+	return
+}
+
+// UnloadedFields remembers the fields of a model left unloaded by QuerySeter.Only/Defer.
+// embed it in a model so Update without cols keeps the stored values of those fields:
+//
+//	type Post struct {
+//		orm.UnloadedFields
+//		ID      int
+//		Content string
+//	}
+type UnloadedFields struct {
+	names []string
+}
+
+// set the unloaded field names of a model embedding UnloadedFields, nil when all are loaded.
+func setUnloaded(mi *modelInfo, ind reflect.Value, names []string) {
+	if mi.unloaded == nil || !ind.CanAddr() {
+		return
+	}
+	ind.FieldByIndex(mi.unloaded).Addr().Interface().(*UnloadedFields).names = names
+}
+
+// get the unloaded field names of a model embedding UnloadedFields.
+func getUnloaded(mi *modelInfo, ind reflect.Value) []string {
+	if mi.unloaded == nil {
+		return nil
+	}
+	return ind.FieldByIndex(mi.unloaded).Interface().(UnloadedFields).names
+}
+
+// read all rows of an EXPLAIN result into Params.
+func readPlanRows(rs *sql.Rows, err error) ([]Params, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rs.Close()
+
+	columns, err := rs.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var plan []Params
+	for rs.Next() {
+		refs := make([]interface{}, len(columns))
+		for i := range refs {
+			var ref interface{}
+			refs[i] = &ref
+		}
+		if err := rs.Scan(refs...); err != nil {
+			return nil, err
+		}
+		row := make(Params, len(columns))
+		for i, col := range columns {
+			val := *refs[i].(*interface{})
+			if b, ok := val.([]byte); ok {
+				val = string(b)
+			}
+			row[col] = val
+		}
+		plan = append(plan, row)
+	}
+	return plan, rs.Err()
+}
+
+// run fn in a transaction, q is used as it is if a transaction can not be started from it.
+func withTx(q dbQuerier, fn func(dbQuerier) error) (err error) {
+	db, ok := q.(txer)
+	if !ok {
+		return fn(q)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+		if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+	return fn(tx)
+}
+
+// add the tenant column of a tenant Ormer to the where columns of a model, unless it is there already.
+func appendTenantWhere(mi *modelInfo, tenant interface{}, whereCols []string, args []interface{}) ([]string, []interface{}) {
+	fi := mi.fields.tenant
+	if fi == nil || tenant == nil {
+		return whereCols, args
+	}
+	for _, col := range whereCols {
+		if col == fi.column {
+			return whereCols, args
+		}
+	}
+	return append(whereCols, fi.column), append(args, tenant)
+}
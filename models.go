@@ -15,6 +15,7 @@
 package orm
 
 import (
+	"reflect"
 	"sync"
 )
 
@@ -40,6 +41,7 @@ type _modelCache struct {
 	orders          []string
 	cache           map[string]*modelInfo
 	cacheByFullName map[string]*modelInfo
+	cacheByType     sync.Map // model info by struct type, filled on the first lookup
 	done            bool
 }
 
@@ -73,6 +75,17 @@ func (mc *_modelCache) getByFullName(name string) (mi *modelInfo, ok bool) {
 	return
 }
 
+// get model info by struct type
+func (mc *_modelCache) getByType(typ reflect.Type) (mi *modelInfo, ok bool) {
+	if v, ok := mc.cacheByType.Load(typ); ok {
+		return v.(*modelInfo), true
+	}
+	if mi, ok = mc.cacheByFullName[getFullName(typ)]; ok {
+		mc.cacheByType.Store(typ, mi)
+	}
+	return
+}
+
 // set model info to collection
 func (mc *_modelCache) set(table string, mi *modelInfo) *modelInfo {
 	mii := mc.cache[table]
@@ -89,6 +102,14 @@ func (mc *_modelCache) clean() {
 	mc.orders = make([]string, 0)
 	mc.cache = make(map[string]*modelInfo)
 	mc.cacheByFullName = make(map[string]*modelInfo)
+	mc.cacheByType.Range(func(typ, _ interface{}) bool {
+		mc.cacheByType.Delete(typ)
+		return true
+	})
+	schemaModels.Range(func(key, _ interface{}) bool {
+		schemaModels.Delete(key)
+		return true
+	})
 	mc.done = false
 }
 
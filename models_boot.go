@@ -87,6 +87,11 @@ func registerModel(aliasName, PrefixOrSuffix string, model interface{}, isPrefix
 	mi.manual = true
 	mi.aliasName = aliasName
 	mi.schema = schema
+	mi.scopes = getTableScopes(val)
+	mi.ordering = getTableOrdering(val)
+	mi.defCond = getTableDefaultCond(val)
+	mi.filters = getTableFilterFields(val)
+	mi.unloaded = getUnloadedIndex(typ)
 
 	modelCache.set(table, mi)
 }
@@ -26,6 +26,7 @@ var errSkipField = errors.New("skip field")
 // field info collection
 type fields struct {
 	pk            *fieldInfo
+	tenant        *fieldInfo
 	columns       map[string]*fieldInfo
 	fields        map[string]*fieldInfo
 	fieldsLow     map[string]*fieldInfo
@@ -120,6 +121,8 @@ type fieldInfo struct {
 	initial             StrTo // store the default value
 	size                int
 	toText              bool
+	toJSON              bool // struct, map or slice stored as marshalled json
+	tenant              bool // tenant column scoped by Ormer.WithTenant
 	sequence            bool //主键是否为sequence自增字段（oralce）
 	autoNow             bool
 	autoNowAdd          bool
@@ -247,6 +250,15 @@ checkType:
 			}
 		}
 
+		if t := tags["type"]; (t == "json" || t == "jsonb") && isJSONKind(sf.Type) {
+			fieldType = TypeJSONField
+			if t == "jsonb" {
+				fieldType = TypeJsonbField
+			}
+			fi.toJSON = true
+			break checkType
+		}
+
 		fieldType, err = getFieldType(addrField)
 		if err != nil {
 			goto end
@@ -313,6 +325,7 @@ checkType:
 	fi.auto = attrs["auto"]
 	fi.pk = attrs["pk"]
 	fi.unique = attrs["unique"]
+	fi.tenant = attrs["tenant"]
 
 	//增加pk 为sequence时的处理
 	fi.sequence = attrs["sequence"]
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sync"
 )
 
 // single model info
@@ -35,6 +36,12 @@ type modelInfo struct {
 	//2023-12-24
 	aliasName string //数据库别名
 	schema    string //schema名称
+	scopes    map[string]func(QuerySeter) QuerySeter
+	ordering  []string
+	defCond   *Condition
+	filters   []string   // field paths allowed in filter documents
+	origin    *modelInfo // registered model info of a UsingSchema copy
+	unloaded  []int      // index of the embedded UnloadedFields
 }
 
 // new model info
@@ -94,6 +101,16 @@ func addModelFields(mi *modelInfo, ind reflect.Value, mName string, index []int)
 				mi.fields.pk = fi
 			}
 		}
+		if fi.tenant {
+			if mi.fields.tenant != nil {
+				err = fmt.Errorf("one model must have one tenant field only")
+				break
+			} else if fi.fieldType&IsIntegerField == 0 && fi.fieldType != TypeVarCharField && fi.fieldType != TypeCharField {
+				err = fmt.Errorf("tenant field must be an integer or string")
+				break
+			}
+			mi.fields.tenant = fi
+		}
 	}
 
 	if err != nil {
@@ -149,3 +166,39 @@ func newM2MModelInfo(m1, m2 *modelInfo) (mi *modelInfo) {
 	mi.uniques = []string{f1.column, f2.column}
 	return
 }
+
+// copies of model info bound to another schema, by registered model info and schema.
+var schemaModels sync.Map
+
+type schemaModelKey struct {
+	mi     *modelInfo
+	schema string
+}
+
+// get the model info with its table in schema.
+// empty schema returns the registered model info.
+func (mi *modelInfo) inSchema(schema string) *modelInfo {
+	if mi.origin != nil {
+		mi = mi.origin
+	}
+	if schema == "" {
+		return mi
+	}
+	key := schemaModelKey{mi, schema}
+	if smi, ok := schemaModels.Load(key); ok {
+		return smi.(*modelInfo)
+	}
+	smi := *mi
+	smi.schema = schema
+	smi.origin = mi
+	v, _ := schemaModels.LoadOrStore(key, &smi)
+	return v.(*modelInfo)
+}
+
+// get the related model info in the same schema override as mi.
+func (mi *modelInfo) sameSchema(rmi *modelInfo) *modelInfo {
+	if mi.origin == nil {
+		return rmi
+	}
+	return rmi.inSchema(mi.schema)
+}
@@ -16,6 +16,7 @@ package orm
 
 import (
 	"database/sql"
+	sqldriver "database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -29,6 +30,13 @@ import (
 	// _ "github.com/pingcap/tidb"
 )
 
+// A slice converted to one value by database/sql.
+type ValuerStrings []string
+
+func (e ValuerStrings) Value() (sqldriver.Value, error) {
+	return strings.Join(e, ","), nil
+}
+
 // A slice string field.
 type SliceStringField []string
 
@@ -232,6 +240,7 @@ type UserBig struct {
 }
 
 type User struct {
+	UnloadedFields
 	ID           int    `orm:"column(id)"`
 	UserName     string `orm:"size(30);unique"`
 	Email        string `orm:"size(100)"`
@@ -264,6 +273,35 @@ func (u *User) TableUnique() [][]string {
 	}
 }
 
+func (u *User) Scopes() map[string]func(QuerySeter) QuerySeter {
+	return map[string]func(QuerySeter) QuerySeter{
+		"staff": func(qs QuerySeter) QuerySeter {
+			return qs.Filter("is_staff", true)
+		},
+		"recent": func(qs QuerySeter) QuerySeter {
+			return qs.OrderBy("-id")
+		},
+	}
+}
+
+func (u *User) TableFilterFields() []string {
+	return []string{"id", "user_name", "status", "is_staff", "created", "profile__age"}
+}
+
+type DefaultScoped struct {
+	ID       int
+	Name     string
+	IsActive bool
+}
+
+func (d *DefaultScoped) TableOrdering() []string {
+	return []string{"-id"}
+}
+
+func (d *DefaultScoped) TableDefaultCond() *Condition {
+	return NewCondition().And("is_active", true)
+}
+
 func NewUser() *User {
 	obj := new(User)
 	return obj
@@ -411,6 +449,38 @@ type PtrPk struct {
 	Positive bool
 }
 
+type JSONAddress struct {
+	City   string
+	Street string
+}
+
+type DataJSON struct {
+	ID      int
+	Address JSONAddress       `orm:"type(json)"`
+	Extra   *JSONAddress      `orm:"null;type(jsonb)"`
+	Meta    map[string]string `orm:"type(json)"`
+	Tags    []string          `orm:"type(json)"`
+}
+
+type TenantItem struct {
+	ID       int
+	TenantID int `orm:"tenant"`
+	Name     string
+}
+
+type TenantTag struct {
+	ID       int
+	TenantID int `orm:"tenant"`
+	Name     string
+	Items    []*TenantItem `orm:"rel(m2m);rel_through(github.com/astaxie/beego/orm.TenantTagItem)"`
+}
+
+type TenantTagItem struct {
+	ID   int
+	Tag  *TenantTag  `orm:"rel(fk)"`
+	Item *TenantItem `orm:"rel(fk)"`
+}
+
 var DBARGS = struct {
 	Driver string
 	Source string
@@ -33,6 +33,7 @@ var supportTag = map[string]int{
 	"auto":         1,
 	"auto_now":     1,
 	"auto_now_add": 1,
+	"tenant":       1,
 	"sequence":     2,
 	"size":         2,
 	"column":       2,
@@ -106,6 +107,71 @@ func getTableUnique(val reflect.Value) [][]string {
 	return nil
 }
 
+// get named query scopes from method.
+func getTableScopes(val reflect.Value) map[string]func(QuerySeter) QuerySeter {
+	fun := val.MethodByName("Scopes")
+	if fun.IsValid() {
+		vals := fun.Call([]reflect.Value{})
+		if len(vals) > 0 && vals[0].CanInterface() {
+			if d, ok := vals[0].Interface().(map[string]func(QuerySeter) QuerySeter); ok {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+// get the index of the UnloadedFields embedded in the model, nil without it.
+func getUnloadedIndex(typ reflect.Type) []int {
+	sf, ok := typ.FieldByName("UnloadedFields")
+	if !ok || !sf.Anonymous || sf.Type != reflect.TypeOf(UnloadedFields{}) {
+		return nil
+	}
+	return sf.Index
+}
+
+// get table default ordering from method.
+func getTableOrdering(val reflect.Value) []string {
+	fun := val.MethodByName("TableOrdering")
+	if fun.IsValid() {
+		vals := fun.Call([]reflect.Value{})
+		if len(vals) > 0 && vals[0].CanInterface() {
+			if d, ok := vals[0].Interface().([]string); ok {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+// get table filterable field paths from method.
+func getTableFilterFields(val reflect.Value) []string {
+	fun := val.MethodByName("TableFilterFields")
+	if fun.IsValid() {
+		vals := fun.Call([]reflect.Value{})
+		if len(vals) > 0 && vals[0].CanInterface() {
+			if d, ok := vals[0].Interface().([]string); ok {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
+// get table default condition from method.
+func getTableDefaultCond(val reflect.Value) *Condition {
+	fun := val.MethodByName("TableDefaultCond")
+	if fun.IsValid() {
+		vals := fun.Call([]reflect.Value{})
+		if len(vals) > 0 && vals[0].CanInterface() {
+			if d, ok := vals[0].Interface().(*Condition); ok && d != nil && !d.IsEmpty() {
+				return d
+			}
+		}
+	}
+	return nil
+}
+
 // get snaked column name
 func getColumnName(ft int, addrField reflect.Value, sf reflect.StructField, col string) string {
 	column := col
@@ -202,6 +268,26 @@ func getFieldType(val reflect.Value) (ft int, err error) {
 	return
 }
 
+// whether the go type can be stored as a marshalled json column
+func isJSONKind(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Map, reflect.Array:
+		return true
+	case reflect.Slice:
+		return typ.Elem().Kind() != reflect.Uint8
+	case reflect.Struct:
+		if typ == reflect.TypeOf(time.Time{}) {
+			return false
+		}
+		// sql.NullString and friends keep their own column types
+		return !reflect.PtrTo(typ).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem())
+	}
+	return false
+}
+
 // parse struct tag string
 func parseStructTag(data string) (attrs map[string]bool, tags map[string]string) {
 	attrs = make(map[string]bool)
@@ -82,6 +82,8 @@ var (
 	ErrStmtClosed    = errors.New("<QuerySeter> stmt already closed")
 	ErrArgs          = errors.New("<Ormer> args error may be empty")
 	ErrNotImplement  = errors.New("have not implement")
+	ErrTenant        = errors.New("<Ormer> model belongs to another tenant")
+	ErrTenantUpsert  = errors.New("<Ormer.InsertOrUpdate> tenant models cannot be upserted, the conflict row may belong to another tenant")
 )
 
 // Params stores the Params
@@ -91,9 +93,11 @@ type Params map[string]interface{}
 type ParamsList []interface{}
 
 type orm struct {
-	alias *alias
-	db    dbQuerier
-	isTx  bool
+	alias  *alias
+	db     dbQuerier
+	isTx   bool
+	tenant interface{}
+	schema string
 }
 
 var _ Ormer = new(orm)
@@ -108,11 +112,91 @@ func (o *orm) getMiInd(md interface{}, needPtr bool) (mi *modelInfo, ind reflect
 	}
 	name := getFullName(typ)
 	if mi, ok := modelCache.getByFullName(name); ok {
-		return mi, ind
+		return mi.inSchema(o.schema), ind
 	}
 	panic(fmt.Errorf("<Ormer> table: `%s` not found, make sure it was registered with `RegisterModel()`", name))
 }
 
+// return an Ormer scoped to the tenant id.
+// models with an orm:"tenant" field are read, queried and written in the tenant only.
+func (o *orm) WithTenant(id interface{}) Ormer {
+	to := *o
+	to.tenant = id
+	return &to
+}
+
+// return an Ormer whose tables live in schema.
+// the schema replaces the one given to RegisterModelWithSchema, other Ormers are not affected.
+func (o *orm) UsingSchema(schema string) Ormer {
+	so := *o
+	so.schema = schema
+	return &so
+}
+
+// check the tenant field of the model without changing it.
+// a zero tenant is accepted, a model of another tenant returns ErrTenant.
+func (o *orm) checkTenant(mi *modelInfo, ind reflect.Value) error {
+	fi := mi.fields.tenant
+	if o.tenant == nil || fi == nil {
+		return nil
+	}
+	field := ind.FieldByIndex(fi.fieldIndex)
+	if !field.IsZero() && ToStr(field.Interface()) != ToStr(o.tenant) {
+		return ErrTenant
+	}
+	return nil
+}
+
+// set the tenant field of the model to the Ormer tenant if it is zero.
+// a model of another tenant returns ErrTenant.
+func (o *orm) setTenant(mi *modelInfo, ind reflect.Value) error {
+	fi := mi.fields.tenant
+	if o.tenant == nil || fi == nil {
+		return nil
+	}
+	field := ind.FieldByIndex(fi.fieldIndex)
+	if !field.IsZero() {
+		return o.checkTenant(mi, ind)
+	}
+	s := StrTo(ToStr(o.tenant))
+	switch {
+	case field.Kind() == reflect.String:
+		field.SetString(s.String())
+	case fi.fieldType&IsPositiveIntegerField > 0:
+		v, err := s.Uint64()
+		if err != nil {
+			return fmt.Errorf("<Ormer.WithTenant> tenant `%v` is not valid for `%s`", o.tenant, fi.fullName)
+		}
+		field.SetUint(v)
+	default:
+		v, err := s.Int64()
+		if err != nil {
+			return fmt.Errorf("<Ormer.WithTenant> tenant `%v` is not valid for `%s`", o.tenant, fi.fullName)
+		}
+		field.SetInt(v)
+	}
+	return nil
+}
+
+// check the rows of pks all exist in the tenant of a tenant Ormer.
+func (o *orm) checkTenantPks(mi *modelInfo, pks []interface{}) error {
+	if o.tenant == nil || mi.fields.tenant == nil || len(pks) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(pks))
+	for _, pk := range pks {
+		seen[ToStr(pk)] = true
+	}
+	num, err := o.QueryTable(mi.table).Unscoped().Filter(mi.fields.pk.name+ExprSep+"in", pks).Count()
+	if err != nil {
+		return err
+	}
+	if num != int64(len(seen)) {
+		return ErrTenant
+	}
+	return nil
+}
+
 // get field info from model info by given field name
 func (o *orm) getFieldInfo(mi *modelInfo, name string) *fieldInfo {
 	fi, ok := mi.fields.GetByAny(name)
@@ -125,20 +209,34 @@ func (o *orm) getFieldInfo(mi *modelInfo, name string) *fieldInfo {
 // read data to model
 func (o *orm) Read(md interface{}, cols ...string) error {
 	mi, ind := o.getMiInd(md, true)
-	return o.alias.DbBaser.Read(o.db, mi, ind, o.alias.TZ, cols, false)
+	if err := o.setTenant(mi, ind); err != nil {
+		return err
+	}
+	return o.alias.DbBaser.Read(o.db, mi, ind, o.alias.TZ, cols, nil, o.tenant)
 }
 
 // read data to model, like Read(), but use "SELECT FOR UPDATE" form
 func (o *orm) ReadForUpdate(md interface{}, cols ...string) error {
+	return o.ReadWithLock(md, RowLock{}, cols...)
+}
+
+// read data to model, like Read(), but lock the row with lock
+func (o *orm) ReadWithLock(md interface{}, lock RowLock, cols ...string) error {
 	mi, ind := o.getMiInd(md, true)
-	return o.alias.DbBaser.Read(o.db, mi, ind, o.alias.TZ, cols, true)
+	if err := o.setTenant(mi, ind); err != nil {
+		return err
+	}
+	return o.alias.DbBaser.Read(o.db, mi, ind, o.alias.TZ, cols, &lock, o.tenant)
 }
 
 // Try to read a row from the database, or insert one if it doesn't exist
 func (o *orm) ReadOrCreate(md interface{}, col1 string, cols ...string) (bool, int64, error) {
 	cols = append([]string{col1}, cols...)
 	mi, ind := o.getMiInd(md, true)
-	err := o.alias.DbBaser.Read(o.db, mi, ind, o.alias.TZ, cols, false)
+	if err := o.setTenant(mi, ind); err != nil {
+		return false, 0, err
+	}
+	err := o.alias.DbBaser.Read(o.db, mi, ind, o.alias.TZ, cols, nil, o.tenant)
 	if err == ErrNoRows {
 		// Create
 		id, err := o.Insert(md)
@@ -160,6 +258,9 @@ func (o *orm) ReadOrCreate(md interface{}, col1 string, cols ...string) (bool, i
 // insert model data to database
 func (o *orm) Insert(md interface{}) (int64, error) {
 	mi, ind := o.getMiInd(md, true)
+	if err := o.setTenant(mi, ind); err != nil {
+		return 0, err
+	}
 	id, err := o.alias.DbBaser.Insert(o.db, mi, ind, o.alias.TZ)
 	if err != nil {
 		return id, err
@@ -196,6 +297,16 @@ func (o *orm) InsertMulti(bulk int, mds interface{}) (int64, error) {
 		return cnt, ErrArgs
 	}
 
+	if o.tenant != nil {
+		for i := 0; i < sind.Len(); i++ {
+			ind := reflect.Indirect(sind.Index(i))
+			mi, _ := o.getMiInd(ind.Interface(), false)
+			if err := o.setTenant(mi, ind); err != nil {
+				return cnt, err
+			}
+		}
+	}
+
 	if bulk <= 1 {
 		for i := 0; i < sind.Len(); i++ {
 			ind := reflect.Indirect(sind.Index(i))
@@ -216,9 +327,79 @@ func (o *orm) InsertMulti(bulk int, mds interface{}) (int64, error) {
 	return cnt, nil
 }
 
+// update some models to database with different values per row.
+// bulk models are updated by one statement.
+func (o *orm) UpdateMulti(bulk int, mds interface{}, cols ...string) (int64, error) {
+	var cnt int64
+
+	sind := reflect.Indirect(reflect.ValueOf(mds))
+
+	switch sind.Kind() {
+	case reflect.Array, reflect.Slice:
+		if sind.Len() == 0 {
+			return cnt, ErrArgs
+		}
+	default:
+		return cnt, ErrArgs
+	}
+
+	mi, _ := o.getMiInd(sind.Index(0).Interface(), false)
+	if o.tenant != nil && mi.fields.tenant != nil {
+		// every model must belong to the tenant
+		inds := make([]reflect.Value, 0, sind.Len())
+		for i := 0; i < sind.Len(); i++ {
+			ind := reflect.Indirect(sind.Index(i))
+			if err := o.setTenant(mi, ind); err != nil {
+				return cnt, err
+			}
+			inds = append(inds, ind)
+		}
+		if err := o.checkTenantPks(mi, prefetchPks(mi, inds)); err != nil {
+			return cnt, err
+		}
+	}
+	if len(cols) > 0 {
+		cols = addAutoNowCols(mi, cols)
+	}
+
+	if bulk <= 1 {
+		for i := 0; i < sind.Len(); i++ {
+			ind := reflect.Indirect(sind.Index(i))
+			num, err := o.alias.DbBaser.Update(o.db, mi, ind, o.alias.TZ, cols, o.tenant)
+			if err != nil {
+				return cnt, err
+			}
+			cnt += num
+		}
+		return cnt, nil
+	}
+	return o.alias.DbBaser.UpdateMulti(o.db, mi, sind, bulk, o.alias, cols, o.tenant)
+}
+
+// add the auto_now fields not in cols, they are refreshed on every update.
+func addAutoNowCols(mi *modelInfo, cols []string) []string {
+	listed := make(map[*fieldInfo]bool, len(cols))
+	for _, col := range cols {
+		if fi, ok := mi.fields.GetByAny(col); ok {
+			listed[fi] = true
+		}
+	}
+	cols = append([]string{}, cols...)
+	for _, fi := range mi.fields.fieldsDB {
+		if fi.autoNow && !listed[fi] {
+			cols = append(cols, fi.name)
+		}
+	}
+	return cols
+}
+
 // InsertOrUpdate data to database
 func (o *orm) InsertOrUpdate(md interface{}, colConflitAndArgs ...string) (int64, error) {
 	mi, ind := o.getMiInd(md, true)
+	if o.tenant != nil && mi.fields.tenant != nil {
+		// the conflict update cannot be limited to the tenant rows
+		return 0, ErrTenantUpsert
+	}
 	id, err := o.alias.DbBaser.InsertOrUpdate(o.db, mi, ind, o.alias, colConflitAndArgs...)
 	if err != nil {
 		return id, err
@@ -233,14 +414,20 @@ func (o *orm) InsertOrUpdate(md interface{}, colConflitAndArgs ...string) (int64
 // cols set the columns those want to update.
 func (o *orm) Update(md interface{}, cols ...string) (int64, error) {
 	mi, ind := o.getMiInd(md, true)
-	return o.alias.DbBaser.Update(o.db, mi, ind, o.alias.TZ, cols)
+	if err := o.setTenant(mi, ind); err != nil {
+		return 0, err
+	}
+	return o.alias.DbBaser.Update(o.db, mi, ind, o.alias.TZ, cols, o.tenant)
 }
 
 // delete model in database
 // cols shows the delete conditions values read from. default is pk
 func (o *orm) Delete(md interface{}, cols ...string) (int64, error) {
 	mi, ind := o.getMiInd(md, true)
-	num, err := o.alias.DbBaser.Delete(o.db, mi, ind, o.alias.TZ, cols)
+	if err := o.setTenant(mi, ind); err != nil {
+		return 0, err
+	}
+	num, err := o.alias.DbBaser.Delete(o.db, mi, ind, o.alias.TZ, cols, o.tenant)
 	if err != nil {
 		return num, err
 	}
@@ -261,8 +448,10 @@ func (o *orm) QueryM2M(md interface{}, name string) QueryM2Mer {
 	default:
 		panic(fmt.Errorf("<Ormer.QueryM2M> model `%s` . name `%s` is not a m2m field", fi.name, mi.fullName))
 	}
-
-	return newQueryM2M(md, o, mi, fi, ind)
+	qm2m := newQueryM2M(md, o, mi, fi, ind).(*queryM2M)
+	// a model of another tenant fails on the first query
+	qm2m.err = o.setTenant(mi, ind)
+	return qm2m
 }
 
 // load related models to md model.
@@ -274,6 +463,11 @@ func (o *orm) QueryM2M(md interface{}, name string) QueryM2Mer {
 //
 // make sure the relation is defined in model struct tags.
 func (o *orm) LoadRelated(md interface{}, name string, args ...interface{}) (int64, error) {
+	if mi, ind := o.getMiInd(md, true); o.tenant != nil {
+		if err := o.setTenant(mi, ind); err != nil {
+			return 0, err
+		}
+	}
 	_, fi, ind, qseter := o.queryRelated(md, name)
 
 	qs := qseter.(*querySet)
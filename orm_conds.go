@@ -15,8 +15,14 @@
 package orm
 
 import (
+	"bytes"
+	sqldriver "database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ExprSep define the expression separation
@@ -151,3 +157,284 @@ func (c *Condition) IsEmpty() bool {
 func (c Condition) clone() *Condition {
 	return &c
 }
+
+// json form of a condition expression.
+type condJSON struct {
+	Expr string            `json:"expr,omitempty"`
+	Args []json.RawMessage `json:"args,omitempty"`
+	Raw  string            `json:"raw,omitempty"`
+	Cond *Condition        `json:"cond,omitempty"`
+	Or   bool              `json:"or,omitempty"`
+	Not  bool              `json:"not,omitempty"`
+}
+
+// MarshalJSON encode the condition as a list of its expressions.
+// time args are encoded as {"$time": "RFC3339"} and models as their pk.
+// for example:
+//
+//	[{"expr":"status__gte","args":[1]},{"cond":[{"expr":"user_name","args":["slene"]}],"or":true,"not":true}]
+func (c Condition) MarshalJSON() ([]byte, error) {
+	list := make([]condJSON, 0, len(c.params))
+	for _, p := range c.params {
+		cj := condJSON{Or: p.isOr, Not: p.isNot}
+		switch {
+		case p.isCond:
+			cj.Cond = p.cond
+		case p.isRaw:
+			cj.Expr = strings.Join(p.exprs, ExprSep)
+			cj.Raw = p.sql
+		default:
+			cj.Expr = strings.Join(p.exprs, ExprSep)
+			for _, arg := range p.args {
+				v, err := encodeCondArg(arg)
+				if err != nil {
+					return nil, err
+				}
+				data, err := json.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				cj.Args = append(cj.Args, data)
+			}
+		}
+		list = append(list, cj)
+	}
+	return json.Marshal(list)
+}
+
+// UnmarshalJSON decode a condition encoded by MarshalJSON.
+// integer numbers are decoded as int64 and other numbers as float64.
+func (c *Condition) UnmarshalJSON(data []byte) error {
+	var list []condJSON
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("<Condition.UnmarshalJSON> %s", err)
+	}
+	params := make([]condValue, 0, len(list))
+	for _, cj := range list {
+		p := condValue{isOr: cj.Or, isNot: cj.Not}
+		switch {
+		case cj.Cond != nil:
+			if cj.Expr != "" || cj.Raw != "" || len(cj.Args) > 0 {
+				return fmt.Errorf("<Condition.UnmarshalJSON> cond cannot have expr, raw or args")
+			}
+			p.cond = cj.Cond
+			p.isCond = true
+		case cj.Raw != "":
+			if cj.Expr == "" || len(cj.Args) > 0 || cj.Or || cj.Not {
+				return fmt.Errorf("<Condition.UnmarshalJSON> raw `%s` needs an expr and cannot have args, or and not", cj.Raw)
+			}
+			p.exprs = strings.Split(cj.Expr, ExprSep)
+			p.sql = cj.Raw
+			p.isRaw = true
+		default:
+			if cj.Expr == "" || len(cj.Args) == 0 {
+				return fmt.Errorf("<Condition.UnmarshalJSON> expr and args cannot empty")
+			}
+			p.exprs = strings.Split(cj.Expr, ExprSep)
+			for _, raw := range cj.Args {
+				var v interface{}
+				dec := json.NewDecoder(bytes.NewReader(raw))
+				dec.UseNumber()
+				if err := dec.Decode(&v); err != nil {
+					return fmt.Errorf("<Condition.UnmarshalJSON> %s", err)
+				}
+				arg, err := decodeCondArg(v)
+				if err != nil {
+					return err
+				}
+				p.args = append(p.args, arg)
+			}
+		}
+		params = append(params, p)
+	}
+	c.params = params
+	return nil
+}
+
+// encode a condition arg to a json value.
+func encodeCondArg(arg interface{}) (interface{}, error) {
+	switch v := arg.(type) {
+	case nil, bool, string, json.Number:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case time.Time:
+		return map[string]string{"$time": v.Format(time.RFC3339Nano)}, nil
+	case Fielder:
+		return encodeCondArg(v.RawValue())
+	case sqldriver.Valuer:
+		value, err := v.Value()
+		if err != nil {
+			return nil, err
+		}
+		return encodeCondArg(value)
+	}
+
+	val := reflect.ValueOf(arg)
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			return nil, nil
+		}
+		return encodeCondArg(val.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return val.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), nil
+	case reflect.Bool:
+		return val.Bool(), nil
+	case reflect.String:
+		return val.String(), nil
+	case reflect.Slice, reflect.Array:
+		list := make([]interface{}, val.Len())
+		for i := range list {
+			v, err := encodeCondArg(val.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case reflect.Struct:
+		if mi, ok := modelCache.getByFullName(getFullName(val.Type())); ok {
+			_, pk, _ := getExistPk(mi, val)
+			return encodeCondArg(pk)
+		}
+	}
+	return nil, fmt.Errorf("<Condition.MarshalJSON> cannot marshal arg of type %T", arg)
+}
+
+// decode a json value to a condition arg.
+func decodeCondArg(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i := range v {
+			arg, err := decodeCondArg(v[i])
+			if err != nil {
+				return nil, err
+			}
+			list[i] = arg
+		}
+		return list, nil
+	case map[string]interface{}:
+		if s, ok := v["$time"].(string); ok && len(v) == 1 {
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, fmt.Errorf("<Condition.UnmarshalJSON> %s", err)
+			}
+			return t, nil
+		}
+		return nil, fmt.Errorf("<Condition.UnmarshalJSON> unknown arg object %v", v)
+	}
+	return v, nil
+}
+
+// String render the condition as a readable expression.
+// for example:
+//
+//	status >= 1 AND NOT (user_name = "slene" OR profile__age BETWEEN 18 AND 30)
+func (c Condition) String() string {
+	var buf strings.Builder
+	for i, p := range c.params {
+		if i > 0 {
+			if p.isOr {
+				buf.WriteString(" OR ")
+			} else {
+				buf.WriteString(" AND ")
+			}
+		}
+		if p.isNot {
+			buf.WriteString("NOT ")
+		}
+		switch {
+		case p.isCond:
+			buf.WriteString("(" + p.cond.String() + ")")
+		case p.isRaw:
+			buf.WriteString(strings.Join(p.exprs, ExprSep) + " " + p.sql)
+		default:
+			buf.WriteString(condExprString(p.exprs, p.args))
+		}
+	}
+	return buf.String()
+}
+
+// render an expression like "profile__age__gte" with its args.
+func condExprString(exprs []string, args []interface{}) string {
+	operator := "exact"
+	if num := len(exprs); num > 1 && operators[exprs[num-1]] {
+		operator = exprs[num-1]
+		exprs = exprs[:num-1]
+	}
+	path := strings.Join(exprs, ExprSep)
+
+	var values []string
+	for _, arg := range args {
+		val := reflect.ValueOf(arg)
+		if _, ok := arg.([]byte); !ok && (val.Kind() == reflect.Slice || val.Kind() == reflect.Array) {
+			for i := 0; i < val.Len(); i++ {
+				values = append(values, condArgString(val.Index(i).Interface()))
+			}
+		} else {
+			values = append(values, condArgString(arg))
+		}
+	}
+	value := strings.Join(values, ", ")
+
+	switch operator {
+	case "exact", "eq":
+		if value == "NULL" {
+			return path + " IS NULL"
+		}
+		return path + " = " + value
+	case "ne", "nq":
+		return path + " != " + value
+	case "gt":
+		return path + " > " + value
+	case "gte":
+		return path + " >= " + value
+	case "lt":
+		return path + " < " + value
+	case "lte":
+		return path + " <= " + value
+	case "in":
+		return path + " IN (" + value + ")"
+	case "between":
+		return path + " BETWEEN " + strings.Join(values, " AND ")
+	case "isnull":
+		if value == "false" {
+			return path + " IS NOT NULL"
+		}
+		return path + " IS NULL"
+	}
+	return path + " " + strings.ToUpper(operator) + " " + value
+}
+
+// render a condition arg.
+func condArgString(arg interface{}) string {
+	if arg == nil {
+		return "NULL"
+	}
+	if v, err := encodeCondArg(arg); err == nil {
+		arg = v
+	}
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return strconv.Quote(v)
+	case map[string]string:
+		return strconv.Quote(v["$time"])
+	}
+	return fmt.Sprint(arg)
+}
@@ -0,0 +1,131 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+// FieldPath is a model field path like "Profile__Age", the typed builders
+// generated by cmd/ormgen are made of it.
+// for example:
+//
+//	qs.SetCond(UserFields.Profile.Age.Gt(18).AndCond(UserFields.UserName.Startswith("a")))
+//	qs.OrderBy(UserFields.Profile.Age.Desc())
+type FieldPath string
+
+// String return the path used in Filter, OrderBy and Condition expressions.
+func (f FieldPath) String() string {
+	return string(f)
+}
+
+// Join return the path of a field of the related model.
+func (f FieldPath) Join(name string) FieldPath {
+	if f == "" {
+		return FieldPath(name)
+	}
+	return f + ExprSep + FieldPath(name)
+}
+
+// Expr return the path with the operator, e.g. "Profile__Age__gt".
+func (f FieldPath) Expr(operator string) string {
+	return string(f) + ExprSep + operator
+}
+
+// Asc return the ascending order expression.
+func (f FieldPath) Asc() string {
+	return string(f)
+}
+
+// Desc return the descending order expression.
+func (f FieldPath) Desc() string {
+	return "-" + string(f)
+}
+
+// Eq return the condition field = value.
+func (f FieldPath) Eq(value interface{}) *Condition {
+	return NewCondition().And(string(f), value)
+}
+
+// Ne return the condition field != value.
+func (f FieldPath) Ne(value interface{}) *Condition {
+	return NewCondition().And(f.Expr("ne"), value)
+}
+
+// Iexact return the case insensitive condition field = value.
+func (f FieldPath) Iexact(value string) *Condition {
+	return NewCondition().And(f.Expr("iexact"), value)
+}
+
+// Gt return the condition field > value.
+func (f FieldPath) Gt(value interface{}) *Condition {
+	return NewCondition().And(f.Expr("gt"), value)
+}
+
+// Gte return the condition field >= value.
+func (f FieldPath) Gte(value interface{}) *Condition {
+	return NewCondition().And(f.Expr("gte"), value)
+}
+
+// Lt return the condition field < value.
+func (f FieldPath) Lt(value interface{}) *Condition {
+	return NewCondition().And(f.Expr("lt"), value)
+}
+
+// Lte return the condition field <= value.
+func (f FieldPath) Lte(value interface{}) *Condition {
+	return NewCondition().And(f.Expr("lte"), value)
+}
+
+// Contains return the condition field LIKE %value%.
+func (f FieldPath) Contains(value string) *Condition {
+	return NewCondition().And(f.Expr("contains"), value)
+}
+
+// Icontains return the case insensitive condition field LIKE %value%.
+func (f FieldPath) Icontains(value string) *Condition {
+	return NewCondition().And(f.Expr("icontains"), value)
+}
+
+// Startswith return the condition field LIKE value%.
+func (f FieldPath) Startswith(value string) *Condition {
+	return NewCondition().And(f.Expr("startswith"), value)
+}
+
+// Istartswith return the case insensitive condition field LIKE value%.
+func (f FieldPath) Istartswith(value string) *Condition {
+	return NewCondition().And(f.Expr("istartswith"), value)
+}
+
+// Endswith return the condition field LIKE %value.
+func (f FieldPath) Endswith(value string) *Condition {
+	return NewCondition().And(f.Expr("endswith"), value)
+}
+
+// Iendswith return the case insensitive condition field LIKE %value.
+func (f FieldPath) Iendswith(value string) *Condition {
+	return NewCondition().And(f.Expr("iendswith"), value)
+}
+
+// In return the condition field IN (values...).
+func (f FieldPath) In(values ...interface{}) *Condition {
+	return NewCondition().And(f.Expr("in"), values...)
+}
+
+// Between return the condition field BETWEEN from AND to.
+func (f FieldPath) Between(from, to interface{}) *Condition {
+	return NewCondition().And(f.Expr("between"), from, to)
+}
+
+// IsNull return the condition field IS NULL, or IS NOT NULL when null is false.
+func (f FieldPath) IsNull(null bool) *Condition {
+	return NewCondition().And(f.Expr("isnull"), null)
+}
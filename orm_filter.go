@@ -0,0 +1,467 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reserved keys of filter documents.
+const (
+	FilterKeyOrder  = "order"
+	FilterKeyLimit  = "limit"
+	FilterKeyOffset = "offset"
+	FilterKeyFilter = "filter" // json documents only
+)
+
+// FilterOptions control the parsing of filter documents.
+type FilterOptions struct {
+	// field paths allowed in filters and ordering, like "status" or "profile__age".
+	// empty uses the TableFilterFields method of the model.
+	Fields []string
+	// the largest limit accepted, 0 means no maximum.
+	MaxLimit int64
+}
+
+// FilterQuery is a filter document parsed for a model.
+type FilterQuery struct {
+	Cond   *Condition
+	Orders []string
+	Limit  int64 // 0 means no limit
+	Offset int64
+}
+
+// Apply add the condition, ordering and paging to qs.
+func (f *FilterQuery) Apply(qs QuerySeter) QuerySeter {
+	if f.Cond != nil && !f.Cond.IsEmpty() {
+		cond := f.Cond
+		if c := qs.GetCond(); c != nil && !c.IsEmpty() {
+			cond = c.AndCond(cond)
+		}
+		qs = qs.SetCond(cond)
+	}
+	if len(f.Orders) > 0 {
+		qs = qs.OrderBy(f.Orders...)
+	}
+	if f.Limit > 0 {
+		qs = qs.Limit(f.Limit, f.Offset)
+	} else if f.Offset > 0 {
+		qs = qs.Offset(f.Offset)
+	}
+	return qs
+}
+
+// ParseFilterValues parse url query values of the model given like QueryTable.
+// keys are field paths with an optional operator, order, limit and offset.
+// in and between take comma separated values.
+// for example:
+//
+//	?status=1&created__gte=2024-01-01&profile__age__in=18,19&order=-id&limit=10
+func ParseFilterValues(ptrStructOrTableName interface{}, values url.Values, opts *FilterOptions) (*FilterQuery, error) {
+	p, err := newFilterParser(ptrStructOrTableName, opts)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		vs := values[key]
+		switch key {
+		case FilterKeyOrder:
+			for _, v := range vs {
+				if err := p.addOrders(strings.Split(v, ",")); err != nil {
+					return nil, err
+				}
+			}
+		case FilterKeyLimit, FilterKeyOffset:
+			if len(vs) != 1 {
+				return nil, fmt.Errorf("<orm.ParseFilter> `%s` takes one value", key)
+			}
+			n, err := strconv.ParseInt(vs[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("<orm.ParseFilter> `%s` is not a number: %s", key, vs[0])
+			}
+			if err := p.setPaging(key, n); err != nil {
+				return nil, err
+			}
+		default:
+			for _, v := range vs {
+				var args []interface{}
+				if op := filterOperator(key); op == "in" || op == "between" {
+					for _, s := range strings.Split(v, ",") {
+						args = append(args, s)
+					}
+				} else {
+					args = []interface{}{v}
+				}
+				if err := p.addFilter(key, args); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return p.query, nil
+}
+
+// ParseFilterJSON parse a json filter document of the model given like QueryTable.
+// for example:
+//
+//	{"filter": {"status": 1, "profile__age__in": [18, 19]}, "order": ["-id"], "limit": 10, "offset": 20}
+func ParseFilterJSON(ptrStructOrTableName interface{}, data []byte, opts *FilterOptions) (*FilterQuery, error) {
+	p, err := newFilterParser(ptrStructOrTableName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("<orm.ParseFilter> %s", err)
+	}
+	for key := range doc {
+		switch key {
+		case FilterKeyFilter, FilterKeyOrder, FilterKeyLimit, FilterKeyOffset:
+		default:
+			return nil, fmt.Errorf("<orm.ParseFilter> unknown key `%s`", key)
+		}
+	}
+
+	if raw, ok := doc[FilterKeyFilter]; ok {
+		var filter map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&filter); err != nil {
+			return nil, fmt.Errorf("<orm.ParseFilter> `%s` %s", FilterKeyFilter, err)
+		}
+		keys := make([]string, 0, len(filter))
+		for key := range filter {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			var args []interface{}
+			switch v := filter[key].(type) {
+			case []interface{}:
+				if op := filterOperator(key); op != "in" && op != "between" {
+					return nil, fmt.Errorf("<orm.ParseFilter> `%s` takes one value", key)
+				}
+				args = v
+			default:
+				args = []interface{}{v}
+			}
+			if err := p.addFilter(key, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if raw, ok := doc[FilterKeyOrder]; ok {
+		var orders []string
+		if err := json.Unmarshal(raw, &orders); err != nil {
+			var order string
+			if json.Unmarshal(raw, &order) != nil {
+				return nil, fmt.Errorf("<orm.ParseFilter> `%s` must be a string or strings", FilterKeyOrder)
+			}
+			orders = strings.Split(order, ",")
+		}
+		if err := p.addOrders(orders); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range []string{FilterKeyLimit, FilterKeyOffset} {
+		if raw, ok := doc[key]; ok {
+			var n int64
+			if err := json.Unmarshal(raw, &n); err != nil {
+				return nil, fmt.Errorf("<orm.ParseFilter> `%s` is not a number: %s", key, raw)
+			}
+			if err := p.setPaging(key, n); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.query, nil
+}
+
+// parse the filter documents of one model.
+type filterParser struct {
+	mi      *modelInfo
+	allowed map[string]bool
+	opts    FilterOptions
+	query   *FilterQuery
+}
+
+func newFilterParser(ptrStructOrTableName interface{}, opts *FilterOptions) (*filterParser, error) {
+	var (
+		mi   *modelInfo
+		ok   bool
+		name string
+	)
+	if table, isStr := ptrStructOrTableName.(string); isStr {
+		name = nameStrategyMap[defaultNameStrategy](table)
+		mi, ok = modelCache.get(name)
+	} else {
+		name = getFullName(indirectType(reflect.TypeOf(ptrStructOrTableName)))
+		mi, ok = modelCache.getByFullName(name)
+	}
+	if !ok {
+		return nil, fmt.Errorf("<orm.ParseFilter> table name: `%s` not exists", name)
+	}
+
+	p := &filterParser{mi: mi, allowed: make(map[string]bool), query: &FilterQuery{Cond: NewCondition()}}
+	if opts != nil {
+		p.opts = *opts
+	}
+	fields := p.opts.Fields
+	if len(fields) == 0 {
+		fields = mi.filters
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("<orm.ParseFilter> model `%s` has no filterable fields, set FilterOptions.Fields or TableFilterFields", mi.fullName)
+	}
+	for _, field := range fields {
+		path, _, err := p.resolve(strings.Split(field, ExprSep))
+		if err != nil {
+			return nil, err
+		}
+		p.allowed[path] = true
+	}
+	return p, nil
+}
+
+// get the operator of a filter key, empty if it has none.
+func filterOperator(key string) string {
+	if i := strings.LastIndex(key, ExprSep); i > 0 && operators[key[i+len(ExprSep):]] {
+		return key[i+len(ExprSep):]
+	}
+	return ""
+}
+
+// resolve field names to the path of field names and the last field.
+func (p *filterParser) resolve(names []string) (string, *fieldInfo, error) {
+	mi := p.mi
+	var fi *fieldInfo
+	path := make([]string, 0, len(names))
+	for i, name := range names {
+		if i > 0 {
+			switch {
+			case fi.rel && fi.fieldType == RelManyToMany:
+				mi = fi.relThroughModelInfo
+			case fi.rel:
+				mi = fi.relModelInfo
+			case fi.reverse:
+				mi = fi.reverseFieldInfo.mi
+			default:
+				return "", nil, fmt.Errorf("<orm.ParseFilter> `%s` is not a relation of `%s`", fi.name, mi.fullName)
+			}
+		}
+		var ok bool
+		if fi, ok = mi.fields.GetByAny(name); !ok {
+			return "", nil, fmt.Errorf("<orm.ParseFilter> unknown field `%s` of `%s`", name, mi.fullName)
+		}
+		path = append(path, fi.name)
+	}
+	if fi == nil {
+		return "", nil, fmt.Errorf("<orm.ParseFilter> empty field path")
+	}
+	return strings.Join(path, ExprSep), fi, nil
+}
+
+// resolve an allowed field path.
+func (p *filterParser) resolveAllowed(expr string) (string, *fieldInfo, error) {
+	path, fi, err := p.resolve(strings.Split(expr, ExprSep))
+	if err != nil {
+		return "", nil, err
+	}
+	if !p.allowed[path] {
+		return "", nil, fmt.Errorf("<orm.ParseFilter> field `%s` is not filterable", expr)
+	}
+	return path, fi, nil
+}
+
+// add a filter of key like "profile__age__gt" with its values.
+func (p *filterParser) addFilter(key string, args []interface{}) error {
+	expr, op := key, filterOperator(key)
+	if op != "" {
+		expr = key[:len(key)-len(op)-len(ExprSep)]
+	}
+	path, fi, err := p.resolveAllowed(expr)
+	if err != nil {
+		return err
+	}
+
+	// relations compare with the pk of the related model
+	switch {
+	case fi.rel:
+		fi = fi.relModelInfo.fields.pk
+	case fi.reverse:
+		fi = fi.reverseFieldInfo.mi.fields.pk
+	}
+
+	switch op {
+	case "in":
+		if len(args) == 0 {
+			return fmt.Errorf("<orm.ParseFilter> `%s` takes at least one value", key)
+		}
+	case "between":
+		if len(args) != 2 {
+			return fmt.Errorf("<orm.ParseFilter> `%s` takes two values", key)
+		}
+	case "isnull":
+		if len(args) != 1 {
+			return fmt.Errorf("<orm.ParseFilter> `%s` takes one value", key)
+		}
+		null, err := filterBool(args[0])
+		if err != nil {
+			return fmt.Errorf("<orm.ParseFilter> `%s` %s", key, err)
+		}
+		args = []interface{}{null}
+	}
+
+	if op != "isnull" {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			v, err := filterValue(fi, arg)
+			if err != nil {
+				return fmt.Errorf("<orm.ParseFilter> `%s` %s", key, err)
+			}
+			values[i] = v
+		}
+		args = values
+	}
+
+	if op != "" {
+		path += ExprSep + op
+	}
+	p.query.Cond = p.query.Cond.And(path, args...)
+	return nil
+}
+
+// add order expressions like "-id".
+func (p *filterParser) addOrders(orders []string) error {
+	for _, order := range orders {
+		order = strings.TrimSpace(order)
+		if order == "" {
+			continue
+		}
+		desc := strings.HasPrefix(order, "-")
+		path, _, err := p.resolveAllowed(strings.TrimPrefix(order, "-"))
+		if err != nil {
+			return err
+		}
+		if desc {
+			path = "-" + path
+		}
+		p.query.Orders = append(p.query.Orders, path)
+	}
+	return nil
+}
+
+// set limit or offset.
+func (p *filterParser) setPaging(key string, n int64) error {
+	if n < 0 {
+		return fmt.Errorf("<orm.ParseFilter> `%s` must not be negative", key)
+	}
+	if key == FilterKeyLimit {
+		if p.opts.MaxLimit > 0 && n > p.opts.MaxLimit {
+			return fmt.Errorf("<orm.ParseFilter> `%s` must not be greater than %d", key, p.opts.MaxLimit)
+		}
+		p.query.Limit = n
+	} else {
+		p.query.Offset = n
+	}
+	return nil
+}
+
+// get a bool of a json value or query string.
+func filterBool(arg interface{}) (bool, error) {
+	switch v := arg.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("is not a bool: %s", v)
+		}
+		return b, nil
+	}
+	return false, fmt.Errorf("is not a bool: %v", arg)
+}
+
+// convert a json value or query string to the type of the field.
+func filterValue(fi *fieldInfo, arg interface{}) (interface{}, error) {
+	var s string
+	switch v := arg.(type) {
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	case bool:
+		if fi.fieldType != TypeBooleanField {
+			return nil, fmt.Errorf("is not a valid value: %v", v)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("is not a valid value: %v", arg)
+	}
+
+	switch {
+	case fi.fieldType == TypeBooleanField:
+		return filterBool(s)
+	case fi.fieldType&IsPositiveIntegerField > 0:
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("is not a positive integer: %s", s)
+		}
+		return n, nil
+	case fi.fieldType&IsIntegerField > 0:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("is not an integer: %s", s)
+		}
+		return n, nil
+	case fi.fieldType == TypeFloatField || fi.fieldType == TypeDecimalField:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("is not a number: %s", s)
+		}
+		return n, nil
+	case fi.fieldType == TypeDateField || fi.fieldType == TypeDateTimeField || fi.fieldType == TypeTimeField:
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+		for _, layout := range []string{formatDateTime, formatDate, formatTime} {
+			if _, err := time.Parse(layout, s); err == nil {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("is not a time: %s", s)
+	}
+	if _, ok := arg.(string); !ok {
+		return nil, fmt.Errorf("is not a string: %v", arg)
+	}
+	return s, nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypedQuerySeter is a QuerySeter of model T returning typed results.
+// for example:
+//
+//	users, err := orm.Query[User](o).Filter("age__gt", 18).OrderBy("-id").All()
+//	user, err := orm.Query[User](o).Filter("user_name", "slene").One()
+type TypedQuerySeter[T any] struct {
+	qs QuerySeter
+}
+
+// get the model info of T, resolved once per type.
+func getTypedModelInfo[T any](caller string) *modelInfo {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if mi, ok := modelCache.getByType(typ); ok {
+		return mi
+	}
+	panic(fmt.Errorf("%s table: `%s` not found, make sure it was registered with `RegisterModel()`", caller, getFullName(typ)))
+}
+
+// Query return a TypedQuerySeter for model T.
+func Query[T any](o Ormer) *TypedQuerySeter[T] {
+	mi := getTypedModelInfo[T]("<orm.Query>")
+	if oo, ok := o.(*orm); ok {
+		return &TypedQuerySeter[T]{qs: newQuerySet(oo, mi)}
+	}
+	return &TypedQuerySeter[T]{qs: o.QueryTable(new(T))}
+}
+
+// Get read model T by its primary key.
+// id is converted to the pk type, ErrNoRows is returned if it does not exist.
+func Get[T any](o Ormer, id interface{}, cols ...string) (*T, error) {
+	mi := getTypedModelInfo[T]("<orm.Get>")
+	if mi.fields.pk == nil {
+		return nil, ErrMissPK
+	}
+	md := new(T)
+	field := reflect.ValueOf(md).Elem().FieldByIndex(mi.fields.pk.fieldIndex)
+	v := reflect.ValueOf(id)
+	if !v.IsValid() || !pkConvertible(v.Type(), field.Type()) {
+		return nil, fmt.Errorf("<orm.Get> id `%v` is not valid for `%s`", id, mi.fields.pk.fullName)
+	}
+	field.Set(v.Convert(field.Type()))
+	if err := o.Read(md, cols...); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// InsertAll insert models of T in batches of bulk.
+// like InsertMulti, the pks are only set when bulk is 1.
+func InsertAll[T any](o Ormer, bulk int, mds []*T) (int64, error) {
+	getTypedModelInfo[T]("<orm.InsertAll>")
+	if len(mds) == 0 {
+		return 0, nil
+	}
+	return o.InsertMulti(bulk, mds)
+}
+
+// check the id type can be set to the pk field, numbers do not convert to strings.
+func pkConvertible(from, to reflect.Type) bool {
+	if from.AssignableTo(to) {
+		return true
+	}
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch to.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return true
+		}
+	case reflect.String:
+		return to.Kind() == reflect.String
+	}
+	return false
+}
+
+// return a copy with the QuerySeter.
+func (q *TypedQuerySeter[T]) with(qs QuerySeter) *TypedQuerySeter[T] {
+	return &TypedQuerySeter[T]{qs: qs}
+}
+
+// QuerySeter return the untyped QuerySeter.
+func (q *TypedQuerySeter[T]) QuerySeter() QuerySeter {
+	return q.qs
+}
+
+// Filter add condition expression, see QuerySeter.Filter.
+func (q *TypedQuerySeter[T]) Filter(expr string, args ...interface{}) *TypedQuerySeter[T] {
+	return q.with(q.qs.Filter(expr, args...))
+}
+
+// FilterRaw add raw sql, see QuerySeter.FilterRaw.
+func (q *TypedQuerySeter[T]) FilterRaw(expr string, sql string) *TypedQuerySeter[T] {
+	return q.with(q.qs.FilterRaw(expr, sql))
+}
+
+// Exclude add NOT condition, see QuerySeter.Exclude.
+func (q *TypedQuerySeter[T]) Exclude(expr string, args ...interface{}) *TypedQuerySeter[T] {
+	return q.with(q.qs.Exclude(expr, args...))
+}
+
+// FilterByExample add conditions for the non-zero fields of md, see QuerySeter.FilterByExample.
+func (q *TypedQuerySeter[T]) FilterByExample(md *T, opts *ExampleOptions) *TypedQuerySeter[T] {
+	return q.with(q.qs.FilterByExample(md, opts))
+}
+
+// SetCond set condition, see QuerySeter.SetCond.
+func (q *TypedQuerySeter[T]) SetCond(cond *Condition) *TypedQuerySeter[T] {
+	return q.with(q.qs.SetCond(cond))
+}
+
+// Unscoped skip the model default condition and ordering, see QuerySeter.Unscoped.
+func (q *TypedQuerySeter[T]) Unscoped() *TypedQuerySeter[T] {
+	return q.with(q.qs.Unscoped())
+}
+
+// Scope apply model scopes, see QuerySeter.Scope.
+func (q *TypedQuerySeter[T]) Scope(names ...string) *TypedQuerySeter[T] {
+	return q.with(q.qs.Scope(names...))
+}
+
+// Limit set limit and offset, see QuerySeter.Limit.
+func (q *TypedQuerySeter[T]) Limit(limit interface{}, args ...interface{}) *TypedQuerySeter[T] {
+	return q.with(q.qs.Limit(limit, args...))
+}
+
+// Offset set offset, see QuerySeter.Offset.
+func (q *TypedQuerySeter[T]) Offset(offset interface{}) *TypedQuerySeter[T] {
+	return q.with(q.qs.Offset(offset))
+}
+
+// GroupBy add GROUP BY expressions, see QuerySeter.GroupBy.
+func (q *TypedQuerySeter[T]) GroupBy(exprs ...string) *TypedQuerySeter[T] {
+	return q.with(q.qs.GroupBy(exprs...))
+}
+
+// OrderBy add ORDER BY expressions, see QuerySeter.OrderBy.
+func (q *TypedQuerySeter[T]) OrderBy(exprs ...string) *TypedQuerySeter[T] {
+	return q.with(q.qs.OrderBy(exprs...))
+}
+
+// RelatedSel set related models to join, see QuerySeter.RelatedSel.
+func (q *TypedQuerySeter[T]) RelatedSel(params ...interface{}) *TypedQuerySeter[T] {
+	return q.with(q.qs.RelatedSel(params...))
+}
+
+// PrefetchRelated set relations loaded after the query, see QuerySeter.PrefetchRelated.
+func (q *TypedQuerySeter[T]) PrefetchRelated(names ...string) *TypedQuerySeter[T] {
+	return q.with(q.qs.PrefetchRelated(names...))
+}
+
+// Only load the given fields, see QuerySeter.Only.
+func (q *TypedQuerySeter[T]) Only(fields ...string) *TypedQuerySeter[T] {
+	return q.with(q.qs.Only(fields...))
+}
+
+// Defer skip loading the given fields, see QuerySeter.Defer.
+func (q *TypedQuerySeter[T]) Defer(fields ...string) *TypedQuerySeter[T] {
+	return q.with(q.qs.Defer(fields...))
+}
+
+// LoadedFields return the field names loaded with Only/Defer, see QuerySeter.LoadedFields.
+func (q *TypedQuerySeter[T]) LoadedFields() []string {
+	return q.qs.LoadedFields()
+}
+
+// Distinct add DISTINCT, see QuerySeter.Distinct.
+func (q *TypedQuerySeter[T]) Distinct() *TypedQuerySeter[T] {
+	return q.with(q.qs.Distinct())
+}
+
+// ForUpdate lock the selected rows, see QuerySeter.ForUpdate.
+func (q *TypedQuerySeter[T]) ForUpdate(wait ...LockWait) *TypedQuerySeter[T] {
+	return q.with(q.qs.ForUpdate(wait...))
+}
+
+// ForShare lock the selected rows in share mode, see QuerySeter.ForShare.
+func (q *TypedQuerySeter[T]) ForShare(wait ...LockWait) *TypedQuerySeter[T] {
+	return q.with(q.qs.ForShare(wait...))
+}
+
+// All return all models of the query.
+func (q *TypedQuerySeter[T]) All(cols ...string) ([]*T, error) {
+	var list []*T
+	if _, err := q.qs.All(&list, cols...); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// One return the first model of the query, ErrNoRows if there is none.
+func (q *TypedQuerySeter[T]) One(cols ...string) (*T, error) {
+	md := new(T)
+	if err := q.qs.One(md, cols...); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// InBatches walk all models in batches of size ordered by primary key, see QuerySeter.InBatches.
+func (q *TypedQuerySeter[T]) InBatches(size int, fn func(batch []*T) error) error {
+	return q.qs.InBatches(size, func(batch interface{}) error {
+		return fn(batch.([]*T))
+	})
+}
+
+// Count return the count of the query.
+func (q *TypedQuerySeter[T]) Count() (int64, error) {
+	return q.qs.Count()
+}
+
+// Exist check the query has rows.
+func (q *TypedQuerySeter[T]) Exist() bool {
+	return q.qs.Exist()
+}
+
+// Update update the matched rows with values.
+func (q *TypedQuerySeter[T]) Update(values Params) (int64, error) {
+	return q.qs.Update(values)
+}
+
+// Delete delete the matched rows.
+func (q *TypedQuerySeter[T]) Delete() (int64, error) {
+	return q.qs.Delete()
+}
+
+// UpdateReturning update the matched rows and return them.
+func (q *TypedQuerySeter[T]) UpdateReturning(values Params) ([]*T, error) {
+	var list []*T
+	if _, err := q.qs.UpdateReturning(values, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteReturning delete the matched rows and return them.
+func (q *TypedQuerySeter[T]) DeleteReturning() ([]*T, error) {
+	var list []*T
+	if _, err := q.qs.DeleteReturning(&list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,309 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"bytes"
+	sqldriver "database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a keyset cursor can not be decoded
+var ErrInvalidCursor = errors.New("<Paginator> invalid cursor")
+
+// Page describe the result of a paginated read
+type Page struct {
+	Num     int64  // rows read into container
+	Total   int64  // total rows, only filled by offset pages
+	Page    int    // page number, only filled by offset pages
+	HasNext bool   // whether there are rows after this page
+	HasPrev bool   // whether there are rows before this page
+	Next    string // cursor of the next page, empty if none
+	Prev    string // cursor of the previous page, empty if none
+}
+
+// Paginator read a QuerySeter page by page.
+// it supports offset pages and keyset pages ordered by the OrderBy fields.
+type Paginator struct {
+	qs      *querySet
+	perPage int64
+}
+
+// NewPaginator create a paginator on the QuerySeter with perPage rows per page.
+// the conditions, relations and orders of the QuerySeter are kept.
+func NewPaginator(qs QuerySeter, perPage int) *Paginator {
+	q, ok := qs.(*querySet)
+	if !ok {
+		panic(fmt.Errorf("<Paginator> unsupported QuerySeter type %T", qs))
+	}
+	if perPage <= 0 {
+		panic(fmt.Errorf("<Paginator> perPage must be greater than 0"))
+	}
+	return &Paginator{qs: q, perPage: int64(perPage)}
+}
+
+// Page read the page num (starts from 1) by LIMIT/OFFSET into container.
+// the total rows are counted with the same conditions.
+func (p *Paginator) Page(num int, container interface{}, cols ...string) (*Page, error) {
+	if num < 1 {
+		num = 1
+	}
+	total, err := p.qs.Count()
+	if err != nil {
+		return nil, err
+	}
+	offset := int64(num-1) * p.perPage
+	qs := *p.qs
+	qs.limit = p.perPage
+	qs.offset = offset
+	n, err := qs.All(container, cols...)
+	if err != nil {
+		return nil, err
+	}
+	return &Page{
+		Num:     n,
+		Total:   total,
+		Page:    num,
+		HasNext: offset+n < total,
+		HasPrev: num > 1,
+	}, nil
+}
+
+// keyset order field
+type keysetField struct {
+	fi   *fieldInfo
+	desc bool
+}
+
+// keyset cursor content
+type keysetCursor struct {
+	Prev   bool          `json:"p,omitempty"`
+	Values []interface{} `json:"v"`
+}
+
+// Cursor read the page located by cursor into container.
+// an empty cursor means the first page. container must be a pointer to slice.
+// the returned Page holds opaque Next/Prev cursors.
+func (p *Paginator) Cursor(cursor string, container interface{}, cols ...string) (*Page, error) {
+	val := reflect.ValueOf(container)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Slice {
+		panic(fmt.Errorf("<Paginator.Cursor> container must be a pointer to slice"))
+	}
+
+	fields, err := p.keysetFields()
+	if err != nil {
+		return nil, err
+	}
+
+	var cur keysetCursor
+	if cursor != "" {
+		if cur, err = decodeKeysetCursor(cursor, fields); err != nil {
+			return nil, err
+		}
+	}
+
+	qs := *p.qs
+	qs.offset = 0
+	qs.limit = p.perPage + 1
+	qs.orders = make([]string, 0, len(fields))
+	for _, kf := range fields {
+		// read backwards for the previous page
+		if kf.desc != cur.Prev {
+			qs.orders = append(qs.orders, "-"+kf.fi.name)
+		} else {
+			qs.orders = append(qs.orders, kf.fi.name)
+		}
+	}
+	if cursor != "" {
+		cond := keysetCond(fields, cur)
+		if qs.cond != nil && !qs.cond.IsEmpty() {
+			cond = qs.cond.AndCond(cond)
+		}
+		qs.cond = cond
+	}
+
+	n, err := qs.All(container, cols...)
+	if err != nil {
+		return nil, err
+	}
+
+	slice := val.Elem()
+	more := n > p.perPage
+	if more {
+		slice.Set(slice.Slice(0, int(p.perPage)))
+		n = p.perPage
+	}
+	if cur.Prev {
+		for i, j := 0, slice.Len()-1; i < j; i, j = i+1, j-1 {
+			a, b := slice.Index(i).Interface(), slice.Index(j).Interface()
+			slice.Index(i).Set(reflect.ValueOf(b))
+			slice.Index(j).Set(reflect.ValueOf(a))
+		}
+	}
+
+	page := &Page{Num: n}
+	if cur.Prev {
+		page.HasPrev = more
+		page.HasNext = true
+	} else {
+		page.HasNext = more
+		page.HasPrev = cursor != ""
+	}
+	if n > 0 {
+		if page.HasNext {
+			if page.Next, err = encodeKeysetCursor(fields, slice.Index(slice.Len()-1), false); err != nil {
+				return nil, err
+			}
+		}
+		if page.HasPrev {
+			if page.Prev, err = encodeKeysetCursor(fields, slice.Index(0), true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return page, nil
+}
+
+// get the keyset fields from the QuerySeter orders, pk is appended as tie-breaker.
+func (p *Paginator) keysetFields() ([]keysetField, error) {
+	mi := p.qs.mi
+	orders := p.qs.getOrders()
+	fields := make([]keysetField, 0, len(orders)+1)
+	hasPk := false
+	for _, order := range orders {
+		name := order
+		desc := false
+		if strings.HasPrefix(name, "-") {
+			name = name[1:]
+			desc = true
+		}
+		fi, ok := mi.fields.GetByAny(name)
+		if !ok || !fi.dbcol || strings.Contains(name, ExprSep) {
+			return nil, fmt.Errorf("<Paginator> keyset order `%s` must be a column of `%s`", order, mi.fullName)
+		}
+		if fi.pk {
+			hasPk = true
+		}
+		fields = append(fields, keysetField{fi: fi, desc: desc})
+	}
+	if !hasPk {
+		if mi.fields.pk == nil {
+			return nil, fmt.Errorf("<Paginator> keyset pagination needs a primary key in `%s`", mi.fullName)
+		}
+		desc := len(fields) > 0 && fields[len(fields)-1].desc
+		fields = append(fields, keysetField{fi: mi.fields.pk, desc: desc})
+	}
+	return fields, nil
+}
+
+// build (f1 > v1) OR (f1 = v1 AND f2 > v2) ...
+func keysetCond(fields []keysetField, cur keysetCursor) *Condition {
+	cond := NewCondition()
+	for i, kf := range fields {
+		sub := NewCondition()
+		for j := 0; j < i; j++ {
+			sub = sub.And(fields[j].fi.name, cur.Values[j])
+		}
+		op := "gt"
+		if kf.desc != cur.Prev {
+			op = "lt"
+		}
+		sub = sub.And(kf.fi.name+ExprSep+op, cur.Values[i])
+		cond = cond.OrCond(sub)
+	}
+	return cond
+}
+
+// encode the keyset values of row into an opaque cursor
+func encodeKeysetCursor(fields []keysetField, row reflect.Value, prev bool) (string, error) {
+	ind := reflect.Indirect(row)
+	cur := keysetCursor{Prev: prev, Values: make([]interface{}, 0, len(fields))}
+	for _, kf := range fields {
+		field := ind.FieldByIndex(kf.fi.fieldIndex)
+		if kf.fi.rel {
+			if field.IsNil() {
+				return "", fmt.Errorf("<Paginator> keyset field `%s` is null", kf.fi.fullName)
+			}
+			field = reflect.Indirect(field).FieldByIndex(kf.fi.relModelInfo.fields.pk.fieldIndex)
+		}
+		var v interface{}
+		if vu, ok := field.Interface().(sqldriver.Valuer); ok {
+			v, _ = vu.Value()
+		} else if field.Kind() == reflect.Ptr {
+			if !field.IsNil() {
+				v = field.Elem().Interface()
+			}
+		} else if kf.fi.isFielder {
+			v = field.Addr().Interface().(Fielder).RawValue()
+		} else {
+			v = field.Interface()
+		}
+		if v == nil {
+			return "", fmt.Errorf("<Paginator> keyset field `%s` is null", kf.fi.fullName)
+		}
+		cur.Values = append(cur.Values, v)
+	}
+	data, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decode a cursor and convert the values back to the field types
+func decodeKeysetCursor(cursor string, fields []keysetField) (cur keysetCursor, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return cur, ErrInvalidCursor
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&cur); err != nil || len(cur.Values) != len(fields) {
+		return cur, ErrInvalidCursor
+	}
+	for i, kf := range fields {
+		fi := kf.fi
+		if fi.rel {
+			fi = fi.relModelInfo.fields.pk
+		}
+		s := StrTo(ToStr(cur.Values[i]))
+		var v interface{}
+		switch ft := fi.fieldType; {
+		case ft&IsPositiveIntegerField > 0:
+			v, err = s.Uint64()
+		case ft&IsIntegerField > 0:
+			v, err = s.Int64()
+		case ft == TypeFloatField || ft == TypeDecimalField:
+			v, err = s.Float64()
+		case ft == TypeBooleanField:
+			v, err = s.Bool()
+		case ft == TypeTimeField || ft == TypeDateField || ft == TypeDateTimeField:
+			v, err = time.Parse(time.RFC3339Nano, s.String())
+		default:
+			v = s.String()
+		}
+		if err != nil {
+			return cur, ErrInvalidCursor
+		}
+		cur.Values[i] = v
+	}
+	return cur, nil
+}
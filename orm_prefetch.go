@@ -0,0 +1,261 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// load related models for every model in the slice.
+// name can be a related path like "Comments__Author",
+// one IN query is issued per relation in the path.
+func (o *orm) LoadRelatedMulti(mds interface{}, name string) (int64, error) {
+	val := reflect.Indirect(reflect.ValueOf(mds))
+	if val.Kind() != reflect.Slice {
+		panic(fmt.Errorf("<Ormer.LoadRelatedMulti> need a slice of models but found `%s`", val.Type()))
+	}
+	typ := val.Type().Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	fullName := getFullName(typ)
+	mi, ok := modelCache.getByFullName(fullName)
+	if !ok {
+		panic(fmt.Errorf("<Ormer> table: `%s` not found, make sure it was registered with `RegisterModel()`", fullName))
+	}
+	inds := make([]reflect.Value, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		if ind := reflect.Indirect(val.Index(i)); ind.IsValid() {
+			if err := o.setTenant(mi, ind); err != nil {
+				return 0, err
+			}
+			inds = append(inds, ind)
+		}
+	}
+	return o.prefetchRelated(mi, inds, strings.Split(name, ExprSep))
+}
+
+// load the relation names[0] for all inds, then walk down the rest of names.
+// return the number of models loaded for the last relation.
+func (o *orm) prefetchRelated(mi *modelInfo, inds []reflect.Value, names []string) (int64, error) {
+	if len(inds) == 0 {
+		return 0, nil
+	}
+	fi := o.getFieldInfo(mi, names[0])
+	if !fi.inModel {
+		panic(fmt.Errorf("<Ormer> name `%s` for model `%s` is not an available rel/reverse field", names[0], mi.fullName))
+	}
+
+	var (
+		rels []reflect.Value
+		err  error
+	)
+	switch {
+	case fi.fieldType == RelForeignKey || fi.fieldType == RelOneToOne:
+		rels, err = o.prefetchRel(fi, inds)
+	case fi.fieldType == RelManyToMany || fi.fieldType == RelReverseMany && fi.reverseFieldInfo.mi.isThrough:
+		rels, err = o.prefetchM2M(mi, fi, inds)
+	case fi.fieldType == RelReverseOne || fi.fieldType == RelReverseMany:
+		rels, err = o.prefetchReverse(mi, fi, inds)
+	default:
+		panic(fmt.Errorf("<Ormer> name `%s` for model `%s` is not an available rel/reverse field", names[0], mi.fullName))
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(names) > 1 {
+		return o.prefetchRelated(fi.relModelInfo, rels, names[1:])
+	}
+	return int64(len(rels)), nil
+}
+
+// query models of mi whose expr is in keys, ordered by pk.
+func (o *orm) prefetchQuery(mi *modelInfo, expr string, keys []interface{}) ([]reflect.Value, error) {
+	qs := newQuerySet(o, mi).(*querySet)
+	qs.cond = NewCondition().And(expr+ExprSep+"in", keys)
+	qs.limit = -1
+	qs.orders = []string{mi.fields.pk.name}
+	container := reflect.New(reflect.SliceOf(reflect.PtrTo(mi.addrField.Elem().Type())))
+	if _, err := qs.All(container.Interface()); err != nil {
+		return nil, err
+	}
+	slice := container.Elem()
+	rels := make([]reflect.Value, slice.Len())
+	for i := range rels {
+		rels[i] = slice.Index(i).Elem()
+	}
+	return rels, nil
+}
+
+// get the pk of a model as map key.
+func prefetchKey(mi *modelInfo, ind reflect.Value) string {
+	_, pk, _ := getExistPk(mi, ind)
+	return ToStr(pk)
+}
+
+// distinct pks of inds.
+func prefetchPks(mi *modelInfo, inds []reflect.Value) []interface{} {
+	keys := make([]interface{}, 0, len(inds))
+	seen := make(map[string]bool, len(inds))
+	for _, ind := range inds {
+		if _, pk, ok := getExistPk(mi, ind); ok && !seen[ToStr(pk)] {
+			seen[ToStr(pk)] = true
+			keys = append(keys, pk)
+		}
+	}
+	return keys
+}
+
+// fk and one to one: query the related models by the fk values.
+func (o *orm) prefetchRel(fi *fieldInfo, inds []reflect.Value) ([]reflect.Value, error) {
+	rmi := fi.relModelInfo
+	refs := make([]reflect.Value, 0, len(inds))
+	for _, ind := range inds {
+		if field := ind.FieldByIndex(fi.fieldIndex); !field.IsNil() {
+			refs = append(refs, field.Elem())
+		}
+	}
+	keys := prefetchPks(rmi, refs)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	rels, err := o.prefetchQuery(rmi, rmi.fields.pk.name, keys)
+	if err != nil {
+		return nil, err
+	}
+	byPk := make(map[string]reflect.Value, len(rels))
+	for _, rel := range rels {
+		byPk[prefetchKey(rmi, rel)] = rel
+	}
+	for _, ind := range inds {
+		field := ind.FieldByIndex(fi.fieldIndex)
+		if field.IsNil() {
+			continue
+		}
+		if rel, ok := byPk[prefetchKey(rmi, field.Elem())]; ok {
+			field.Set(rel.Addr())
+		}
+	}
+	return rels, nil
+}
+
+// reverse one and reverse many: query the related models by their fk to mi.
+func (o *orm) prefetchReverse(mi *modelInfo, fi *fieldInfo, inds []reflect.Value) ([]reflect.Value, error) {
+	keys := prefetchPks(mi, inds)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	rfi := fi.reverseFieldInfo
+	rels, err := o.prefetchQuery(fi.relModelInfo, rfi.name, keys)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string][]reflect.Value, len(inds))
+	for _, rel := range rels {
+		if field := rel.FieldByIndex(rfi.fieldIndex); !field.IsNil() {
+			key := prefetchKey(mi, field.Elem())
+			groups[key] = append(groups[key], rel)
+		}
+	}
+	for _, ind := range inds {
+		prefetchSet(fi, ind, groups[prefetchKey(mi, ind)])
+	}
+	return rels, nil
+}
+
+// many to many: read the pairs from the through table, then the related models.
+func (o *orm) prefetchM2M(mi *modelInfo, fi *fieldInfo, inds []reflect.Value) ([]reflect.Value, error) {
+	keys := prefetchPks(mi, inds)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	rfi, rfiTwo := fi.reverseFieldInfo, fi.reverseFieldInfoTwo
+
+	qs := newQuerySet(o, fi.relThroughModelInfo).(*querySet)
+	qs.cond = NewCondition().And(rfi.name+ExprSep+"in", keys)
+	qs.limit = -1
+	var pairs []ParamsList
+	if _, err := qs.ValuesList(&pairs, rfi.name, rfiTwo.name); err != nil {
+		return nil, err
+	}
+
+	relKeys := make([]interface{}, 0, len(pairs))
+	seen := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		if k := ToStr(pair[1]); !seen[k] {
+			seen[k] = true
+			relKeys = append(relKeys, pair[1])
+		}
+	}
+
+	var rels []reflect.Value
+	if len(relKeys) > 0 {
+		var err error
+		rels, err = o.prefetchQuery(fi.relModelInfo, fi.relModelInfo.fields.pk.name, relKeys)
+		if err != nil {
+			return nil, err
+		}
+	}
+	byPk := make(map[string]reflect.Value, len(rels))
+	for _, rel := range rels {
+		byPk[prefetchKey(fi.relModelInfo, rel)] = rel
+	}
+	groups := make(map[string][]reflect.Value, len(inds))
+	for _, pair := range pairs {
+		if rel, ok := byPk[ToStr(pair[1])]; ok {
+			key := ToStr(pair[0])
+			groups[key] = append(groups[key], rel)
+		}
+	}
+	for _, ind := range inds {
+		prefetchSet(fi, ind, groups[prefetchKey(mi, ind)])
+	}
+	return rels, nil
+}
+
+// assign the loaded models to the relation field of ind.
+func prefetchSet(fi *fieldInfo, ind reflect.Value, rels []reflect.Value) {
+	field := ind.FieldByIndex(fi.fieldIndex)
+	if fi.fieldType == RelReverseOne {
+		if len(rels) > 0 {
+			field.Set(rels[0].Addr())
+		} else {
+			field.Set(reflect.Zero(field.Type()))
+		}
+		return
+	}
+	slice := reflect.MakeSlice(field.Type(), 0, len(rels))
+	for _, rel := range rels {
+		slice = reflect.Append(slice, rel.Addr())
+	}
+	field.Set(slice)
+}
+
+// get the model structs of a container read by QuerySeter.
+func prefetchInds(container interface{}) []reflect.Value {
+	ind := reflect.Indirect(reflect.ValueOf(container))
+	if ind.Kind() != reflect.Slice {
+		return []reflect.Value{ind}
+	}
+	inds := make([]reflect.Value, 0, ind.Len())
+	for i := 0; i < ind.Len(); i++ {
+		if elm := reflect.Indirect(ind.Index(i)); elm.IsValid() {
+			inds = append(inds, elm)
+		}
+	}
+	return inds
+}
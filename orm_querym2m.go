@@ -23,6 +23,7 @@ type queryM2M struct {
 	fi  *fieldInfo
 	qs  *querySet
 	ind reflect.Value
+	err error
 }
 
 // add models to origin models when creating queryM2M.
@@ -33,21 +34,27 @@ type queryM2M struct {
 //
 // make sure the relation is defined in post model struct tag.
 func (o *queryM2M) Add(mds ...interface{}) (int64, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
 	fi := o.fi
-	mi := fi.relThroughModelInfo
+	mi := o.qs.mi
 	mfi := fi.reverseFieldInfo
 	rfi := fi.reverseFieldInfoTwo
 
 	orm := o.qs.orm
 	dbase := orm.alias.DbBaser
 
-	var models []interface{}
 	var otherValues []interface{}
 	var otherNames []string
 
+	var tfi *fieldInfo
+	if orm.tenant != nil {
+		tfi = mi.fields.tenant
+	}
 	for _, colname := range mi.fields.dbcols {
 		if colname != mfi.column && colname != rfi.column && colname != fi.mi.fields.pk.column &&
-			mi.fields.columns[colname] != mi.fields.pk {
+			mi.fields.columns[colname] != mi.fields.pk && (tfi == nil || colname != tfi.column) {
 			otherNames = append(otherNames, colname)
 		}
 	}
@@ -57,24 +64,15 @@ func (o *queryM2M) Add(mds ...interface{}) (int64, error) {
 			mds = append(mds[:i], mds[i+1:]...)
 		}
 	}
-	for _, md := range mds {
-		val := reflect.ValueOf(md)
-		if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
-			for i := 0; i < val.Len(); i++ {
-				v := val.Index(i)
-				if v.CanInterface() {
-					models = append(models, v.Interface())
-				}
-			}
-		} else {
-			models = append(models, md)
-		}
-	}
+	models := getM2MModels(mds)
 
 	_, v1, exist := getExistPk(o.mi, o.ind)
 	if !exist {
 		panic(ErrMissPK)
 	}
+	if err := o.checkTenant(models); err != nil {
+		return 0, err
+	}
 
 	names := []string{mfi.column, rfi.column}
 
@@ -90,18 +88,39 @@ func (o *queryM2M) Add(mds ...interface{}) (int64, error) {
 			if !exist {
 				panic(ErrMissPK)
 			}
+			if err := orm.checkTenant(fi.relModelInfo, ind); err != nil {
+				return 0, err
+			}
 		}
 		values = append(values, v1, v2)
 
 	}
 	names = append(names, otherNames...)
-	values = append(values, otherValues...)
+	if tfi != nil {
+		// every row of the through table belongs to the tenant
+		names = append(names, tfi.column)
+		rows := values
+		values = make([]interface{}, 0, len(rows)/2*len(names))
+		for i := 0; i < len(rows); i += 2 {
+			values = append(values, rows[i], rows[i+1])
+			values = append(values, otherValues...)
+			values = append(values, orm.tenant)
+		}
+	} else {
+		values = append(values, otherValues...)
+	}
 	return dbase.InsertValue(orm.db, mi, true, names, values)
 }
 
 // remove models following the origin model relationship
 func (o *queryM2M) Remove(mds ...interface{}) (int64, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
 	fi := o.fi
+	if err := o.checkTenant(getM2MModels(mds)); err != nil {
+		return 0, err
+	}
 	qs := o.qs.Filter(fi.reverseFieldInfo.name, o.md)
 
 	return qs.Filter(fi.reverseFieldInfoTwo.name+ExprSep+"in", mds).Delete()
@@ -109,6 +128,9 @@ func (o *queryM2M) Remove(mds ...interface{}) (int64, error) {
 
 // check model is existed in relationship of origin model
 func (o *queryM2M) Exist(md interface{}) bool {
+	if o.err != nil {
+		return false
+	}
 	fi := o.fi
 	return o.qs.Filter(fi.reverseFieldInfo.name, o.md).
 		Filter(fi.reverseFieldInfoTwo.name, md).Exist()
@@ -116,18 +138,67 @@ func (o *queryM2M) Exist(md interface{}) bool {
 
 // clean all models in related of origin model
 func (o *queryM2M) Clear() (int64, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
 	fi := o.fi
 	return o.qs.Filter(fi.reverseFieldInfo.name, o.md).Delete()
 }
 
 // count all related models of origin model
 func (o *queryM2M) Count() (int64, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
 	fi := o.fi
 	return o.qs.Filter(fi.reverseFieldInfo.name, o.md).Count()
 }
 
 var _ QueryM2Mer = new(queryM2M)
 
+// check the origin model and the related models exist in the tenant of a tenant Ormer.
+func (o *queryM2M) checkTenant(models []interface{}) error {
+	orm := o.qs.orm
+	if orm.tenant == nil {
+		return nil
+	}
+	if _, pk, ok := getExistPk(o.mi, o.ind); ok {
+		if err := orm.checkTenantPks(o.mi, []interface{}{pk}); err != nil {
+			return err
+		}
+	}
+	rmi := o.fi.relModelInfo
+	pks := make([]interface{}, 0, len(models))
+	for _, md := range models {
+		ind := reflect.Indirect(reflect.ValueOf(md))
+		if ind.Kind() != reflect.Struct {
+			pks = append(pks, ind.Interface())
+		} else if _, pk, ok := getExistPk(rmi, ind); ok {
+			pks = append(pks, pk)
+		}
+	}
+	return orm.checkTenantPks(rmi, pks)
+}
+
+// flatten the models and model slices given to Add and Remove.
+func getM2MModels(mds []interface{}) []interface{} {
+	var models []interface{}
+	for _, md := range mds {
+		val := reflect.ValueOf(md)
+		if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
+			for i := 0; i < val.Len(); i++ {
+				v := val.Index(i)
+				if v.CanInterface() {
+					models = append(models, v.Interface())
+				}
+			}
+		} else {
+			models = append(models, md)
+		}
+	}
+	return models
+}
+
 // create new M2M queryer.
 func newQueryM2M(md interface{}, o *orm, mi *modelInfo, fi *fieldInfo, ind reflect.Value) QueryM2Mer {
 	qm2m := new(queryM2M)
@@ -17,6 +17,8 @@ package orm
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 )
 
 type colValue struct {
@@ -34,6 +36,47 @@ const (
 	ColExcept
 )
 
+// QueryOp is the QuerySeter operation rendered by ToSQL
+type QueryOp int
+
+// define ToSQL operations
+const (
+	OpAll QueryOp = iota
+	OpCount
+	OpUpdate
+	OpDelete
+)
+
+// LockWait is the wait policy of a row lock
+type LockWait int
+
+// define row lock wait policies
+const (
+	// wait until the locked rows are released
+	LockWaitDefault LockWait = iota
+	// fail at once if a row is locked
+	NoWait
+	// skip the locked rows
+	SkipLocked
+)
+
+// RowLock describe the row lock taken by a SELECT.
+// Of holds the model table names or related names to lock, empty locks all tables.
+type RowLock struct {
+	Share bool
+	Of    []string
+	Wait  LockWait
+}
+
+// create a row lock with the optional wait policy.
+func newRowLock(share bool, wait []LockWait) *RowLock {
+	lock := &RowLock{Share: share}
+	if len(wait) > 0 {
+		lock.Wait = wait[0]
+	}
+	return lock
+}
+
 // ColValue do the field raw changes. e.g Nums = Nums + 10. usage:
 // 	Params{
 // 		"Nums": ColValue(Col_Add, 10),
@@ -65,7 +108,11 @@ type querySet struct {
 	groups     []string
 	orders     []string
 	distinct   bool
-	forupdate  bool
+	lock       *RowLock
+	only       []string
+	defers     []string
+	prefetch   []string
+	unscoped   bool
 	orm        *orm
 	ctx        context.Context
 	forContext bool
@@ -100,6 +147,113 @@ func (o querySet) Exclude(expr string, args ...interface{}) QuerySeter {
 	return &o
 }
 
+// ExampleOptions control QuerySeter.FilterByExample.
+// fields are given by field name or column.
+type ExampleOptions struct {
+	// operators of fields instead of exact, e.g. {"UserName": "icontains", "Status": "gte"}.
+	Operators map[string]string
+	// fields filtered even when they hold the zero value.
+	Include []string
+	// a model of the same type holding upper bounds, its non-zero fields
+	// filter BETWEEN the example value and the bound, or lte without an example value.
+	RangeTo interface{}
+}
+
+// add conditions for the non-zero fields of the example model.
+func (o querySet) FilterByExample(md interface{}, opts *ExampleOptions) QuerySeter {
+	if opts == nil {
+		opts = &ExampleOptions{}
+	}
+	ind := o.exampleInd(md)
+	var upper reflect.Value
+	if opts.RangeTo != nil {
+		upper = o.exampleInd(opts.RangeTo)
+	}
+
+	operator := make(map[string]string, len(opts.Operators))
+	for name, op := range opts.Operators {
+		fi := o.exampleField(name)
+		if !operators[op] || op == "in" || op == "between" || op == "isnull" {
+			panic(fmt.Errorf("<QuerySeter.FilterByExample> operator `%s` of `%s` is not supported", op, name))
+		}
+		operator[fi.name] = op
+	}
+	include := make(map[string]bool, len(opts.Include))
+	for _, name := range opts.Include {
+		include[o.exampleField(name).name] = true
+	}
+
+	if o.cond == nil {
+		o.cond = NewCondition()
+	}
+	for _, fi := range o.mi.fields.fieldsDB {
+		value, ok := exampleValue(fi, ind)
+		if fi.toJSON && !include[fi.name] {
+			continue
+		}
+		var bound interface{}
+		hasBound := false
+		if upper.IsValid() {
+			bound, hasBound = exampleValue(fi, upper)
+		}
+		switch {
+		case ok && hasBound:
+			o.cond = o.cond.And(fi.name+ExprSep+"between", value, bound)
+		case hasBound:
+			o.cond = o.cond.And(fi.name+ExprSep+"lte", bound)
+		case ok:
+			if op, has := operator[fi.name]; has {
+				o.cond = o.cond.And(fi.name+ExprSep+op, value)
+			} else {
+				o.cond = o.cond.And(fi.name, value)
+			}
+		case include[fi.name]:
+			if value == nil {
+				o.cond = o.cond.And(fi.name+ExprSep+"isnull", true)
+			} else {
+				o.cond = o.cond.And(fi.name, value)
+			}
+		}
+	}
+	return &o
+}
+
+// get the struct value of an example model of the QuerySeter model.
+func (o *querySet) exampleInd(md interface{}) reflect.Value {
+	ind := reflect.Indirect(reflect.ValueOf(md))
+	if ind.Kind() != reflect.Struct || getFullName(ind.Type()) != o.mi.fullName {
+		panic(fmt.Errorf("<QuerySeter.FilterByExample> example must be a `%s`, got %T", o.mi.fullName, md))
+	}
+	return ind
+}
+
+// get a db field of the QuerySeter model by field name or column.
+func (o *querySet) exampleField(name string) *fieldInfo {
+	fi, ok := o.mi.fields.GetByAny(name)
+	if !ok || !fi.dbcol {
+		panic(fmt.Errorf("<QuerySeter.FilterByExample> unknown field/column name `%s`", name))
+	}
+	return fi
+}
+
+// get the filter value of a field of the example and whether it is not zero.
+// relations give the pk of the related model.
+func exampleValue(fi *fieldInfo, ind reflect.Value) (interface{}, bool) {
+	field := ind.FieldByIndex(fi.fieldIndex)
+	if fi.rel {
+		if field.IsNil() {
+			return nil, false
+		}
+		pk := reflect.Indirect(field).FieldByIndex(fi.relModelInfo.fields.pk.fieldIndex)
+		return pk.Interface(), !pk.IsZero()
+	}
+	value := field.Interface()
+	if f, ok := value.(Fielder); ok {
+		value = f.RawValue()
+	}
+	return value, !field.IsZero()
+}
+
 // set offset number
 func (o *querySet) setOffset(num interface{}) {
 	o.offset = ToInt64(num)
@@ -141,8 +295,25 @@ func (o querySet) Distinct() QuerySeter {
 }
 
 // add FOR UPDATE to SELECT
-func (o querySet) ForUpdate() QuerySeter {
-	o.forupdate = true
+func (o querySet) ForUpdate(wait ...LockWait) QuerySeter {
+	o.lock = newRowLock(false, wait)
+	return &o
+}
+
+// add FOR SHARE to SELECT
+func (o querySet) ForShare(wait ...LockWait) QuerySeter {
+	o.lock = newRowLock(true, wait)
+	return &o
+}
+
+// add FOR UPDATE OF table to SELECT, it can be called for several tables.
+func (o querySet) ForUpdateOf(table string, wait ...LockWait) QuerySeter {
+	lock := newRowLock(false, wait)
+	if o.lock != nil && !o.lock.Share {
+		lock.Of = append(lock.Of, o.lock.Of...)
+	}
+	lock.Of = append(lock.Of, table)
+	o.lock = lock
 	return &o
 }
 
@@ -166,12 +337,115 @@ func (o querySet) RelatedSel(params ...interface{}) QuerySeter {
 	return &o
 }
 
+// load related models of the results after All or One.
+func (o querySet) PrefetchRelated(names ...string) QuerySeter {
+	o.prefetch = append(append([]string{}, o.prefetch...), names...)
+	return &o
+}
+
+// load only the given fields, related paths like "User__Profile__Age" are allowed.
+// pk and relation columns are always loaded.
+func (o querySet) Only(fields ...string) QuerySeter {
+	o.only = append(append([]string{}, o.only...), fields...)
+	return &o
+}
+
+// skip loading the given fields, related paths like "User__Profile__Age" are allowed.
+func (o querySet) Defer(fields ...string) QuerySeter {
+	o.defers = append(append([]string{}, o.defers...), fields...)
+	return &o
+}
+
+// get the field names of the model loaded with Only/Defer, to update only them.
+func (o *querySet) LoadedFields() []string {
+	onlys, err := getLoadFields(o.mi, o.only, false)
+	if err != nil {
+		panic(fmt.Errorf("<QuerySeter.LoadedFields> %s", err))
+	}
+	defers, err := getLoadFields(o.mi, o.defers, true)
+	if err != nil {
+		panic(fmt.Errorf("<QuerySeter.LoadedFields> %s", err))
+	}
+	_, names, _ := getLoadColumns(o.mi, onlys[""], defers[""])
+	return names
+}
+
 // set condition to QuerySeter.
 func (o querySet) SetCond(cond *Condition) QuerySeter {
 	o.cond = cond
 	return &o
 }
 
+// apply the named scopes declared by the model Scopes method in order.
+func (o querySet) Scope(names ...string) QuerySeter {
+	var qs QuerySeter = &o
+	for _, name := range names {
+		fn, ok := o.mi.scopes[name]
+		if !ok {
+			panic(fmt.Errorf("<QuerySeter.Scope> model `%s` has no scope `%s`", o.mi.fullName, name))
+		}
+		qs = fn(qs)
+	}
+	return qs
+}
+
+// skip the default ordering and condition of the model.
+func (o querySet) Unscoped() QuerySeter {
+	o.unscoped = true
+	return &o
+}
+
+// get the condition to execute, the model default condition is added unless Unscoped.
+// the tenant condition of a tenant Ormer is always added.
+func (o *querySet) getCond() *Condition {
+	cond := o.cond
+	if !o.unscoped && o.mi.defCond != nil {
+		cond = andCond(o.mi.defCond, cond)
+	}
+	if fi := o.mi.fields.tenant; fi != nil && o.orm != nil && o.orm.tenant != nil {
+		cond = andCond(NewCondition().And(fi.name, o.orm.tenant), cond)
+	}
+	return cond
+}
+
+// combine the conditions by AND, cond may be empty.
+func andCond(base, cond *Condition) *Condition {
+	if cond == nil || cond.IsEmpty() {
+		return base
+	}
+	return base.AndCond(cond)
+}
+
+// check the update values do not move rows to another tenant.
+func (o *querySet) checkTenant(values Params) error {
+	fi := o.mi.fields.tenant
+	if fi == nil || o.orm.tenant == nil {
+		return nil
+	}
+	for col, val := range values {
+		if f, ok := o.mi.fields.GetByAny(col); ok && f == fi && ToStr(val) != ToStr(o.orm.tenant) {
+			return ErrTenant
+		}
+	}
+	return nil
+}
+
+// get the condition to delete with, a delete without explicit condition still fails.
+func (o *querySet) getDeleteCond() *Condition {
+	if o.cond == nil || o.cond.IsEmpty() {
+		return o.cond
+	}
+	return o.getCond()
+}
+
+// get the orders to execute, the model default ordering is used if OrderBy is not set.
+func (o *querySet) getOrders() []string {
+	if len(o.orders) == 0 && !o.unscoped {
+		return o.mi.ordering
+	}
+	return o.orders
+}
+
 // get condition from QuerySeter
 func (o querySet) GetCond() *Condition {
 	return o.cond
@@ -179,23 +453,73 @@ func (o querySet) GetCond() *Condition {
 
 // return QuerySeter execution result number
 func (o *querySet) Count() (int64, error) {
-	return o.orm.alias.DbBaser.Count(o.orm.db, o, o.mi, o.cond, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.Count(o.orm.db, o, o.mi, o.getCond(), o.orm.alias.TZ)
 }
 
 // check result empty or not after QuerySeter executed
 func (o *querySet) Exist() bool {
-	cnt, _ := o.orm.alias.DbBaser.Count(o.orm.db, o, o.mi, o.cond, o.orm.alias.TZ)
+	cnt, _ := o.orm.alias.DbBaser.Count(o.orm.db, o, o.mi, o.getCond(), o.orm.alias.TZ)
 	return cnt > 0
 }
 
 // execute update with parameters
 func (o *querySet) Update(values Params) (int64, error) {
-	return o.orm.alias.DbBaser.UpdateBatch(o.orm.db, o, o.mi, o.cond, values, o.orm.alias.TZ)
+	if err := o.checkTenant(values); err != nil {
+		return 0, err
+	}
+	return o.orm.alias.DbBaser.UpdateBatch(o.orm.db, o, o.mi, o.getCond(), values, o.orm.alias.TZ)
 }
 
 // execute delete
 func (o *querySet) Delete() (int64, error) {
-	return o.orm.alias.DbBaser.DeleteBatch(o.orm.db, o, o.mi, o.cond, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.DeleteBatch(o.orm.db, o, o.mi, o.getDeleteCond(), o.orm.alias.TZ)
+}
+
+// execute update with parameters and read the updated rows into container.
+func (o *querySet) UpdateReturning(values Params, container interface{}) (int64, error) {
+	if err := o.checkTenant(values); err != nil {
+		return 0, err
+	}
+	return o.orm.alias.DbBaser.UpdateBatchReturning(o.orm.db, o, o.mi, o.getCond(), values, container, o.orm.alias.TZ)
+}
+
+// execute delete and read the deleted rows into container.
+func (o *querySet) DeleteReturning(container interface{}) (int64, error) {
+	return o.orm.alias.DbBaser.DeleteBatchReturning(o.orm.db, o, o.mi, o.getDeleteCond(), container, o.orm.alias.TZ)
+}
+
+// get the sql and args of op without executing it.
+// OpUpdate needs the update values as the first of values.
+// OpDelete gives the primary key select, Delete removes the found rows with IN.
+func (o *querySet) ToSQL(op QueryOp, values ...Params) (string, []interface{}, error) {
+	d := o.orm.alias.DbBaser
+	tz := o.orm.alias.TZ
+	switch op {
+	case OpAll:
+		return d.ReadBatchSQL(o, o.mi, o.getCond(), tz, nil)
+	case OpCount:
+		query, args := d.CountSQL(o, o.mi, o.getCond(), tz)
+		return query, args, nil
+	case OpUpdate:
+		if len(values) == 0 || len(values[0]) == 0 {
+			return "", nil, ErrArgs
+		}
+		query, args := d.UpdateBatchSQL(o, o.mi, o.getCond(), values[0], tz)
+		return query, args, nil
+	case OpDelete:
+		query, args := d.DeleteBatchSQL(o, o.mi, o.getDeleteCond(), tz)
+		return query, args, nil
+	}
+	return "", nil, fmt.Errorf("<QuerySeter.ToSQL> unknown operation %d", op)
+}
+
+// run the dialect EXPLAIN on the All query and return the plan rows.
+func (o *querySet) Explain(analyze bool) ([]Params, error) {
+	query, args, err := o.ToSQL(OpAll)
+	if err != nil {
+		return nil, err
+	}
+	return o.orm.alias.DbBaser.Explain(o.orm.db, query, args, analyze)
 }
 
 // return a insert queryer.
@@ -210,14 +534,18 @@ func (o *querySet) PrepareInsert() (Inserter, error) {
 // query all data and map to containers.
 // cols means the columns when querying.
 func (o *querySet) All(container interface{}, cols ...string) (int64, error) {
-	return o.orm.alias.DbBaser.ReadBatch(o.orm.db, o, o.mi, o.cond, container, o.orm.alias.TZ, cols)
+	num, err := o.orm.alias.DbBaser.ReadBatch(o.orm.db, o, o.mi, o.getCond(), container, o.orm.alias.TZ, cols)
+	if err == nil && num > 0 {
+		err = o.loadPrefetch(container)
+	}
+	return num, err
 }
 
 // query one row data and map to containers.
 // cols means the columns when querying.
 func (o *querySet) One(container interface{}, cols ...string) error {
 	o.limit = 1
-	num, err := o.orm.alias.DbBaser.ReadBatch(o.orm.db, o, o.mi, o.cond, container, o.orm.alias.TZ, cols)
+	num, err := o.orm.alias.DbBaser.ReadBatch(o.orm.db, o, o.mi, o.getCond(), container, o.orm.alias.TZ, cols)
 	if err != nil {
 		return err
 	}
@@ -228,26 +556,86 @@ func (o *querySet) One(container interface{}, cols ...string) error {
 	if num > 1 {
 		return ErrMultiRows
 	}
+	return o.loadPrefetch(container)
+}
+
+// load the PrefetchRelated relations into container.
+func (o *querySet) loadPrefetch(container interface{}) error {
+	if len(o.prefetch) == 0 {
+		return nil
+	}
+	inds := prefetchInds(container)
+	for _, name := range o.prefetch {
+		if _, err := o.orm.prefetchRelated(o.mi, inds, strings.Split(name, ExprSep)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// walk all rows in batches of size ordered by primary key.
+// batch is a []*Model, return an error from fn to stop.
+func (o *querySet) InBatches(size int, fn func(batch interface{}) error) error {
+	if size <= 0 {
+		panic(fmt.Errorf("<QuerySeter.InBatches> size must be greater than 0"))
+	}
+	pk := o.mi.fields.pk
+	if pk == nil {
+		return ErrMissPK
+	}
+	typ := reflect.SliceOf(reflect.PtrTo(o.mi.addrField.Elem().Type()))
+
+	qs := *o
+	qs.orders = []string{pk.name}
+	qs.limit = int64(size)
+	qs.offset = 0
+
+	var last interface{}
+	for {
+		q := qs
+		if last != nil {
+			cond := NewCondition().And(pk.name+ExprSep+"gt", last)
+			if q.cond != nil && !q.cond.IsEmpty() {
+				cond = q.cond.AndCond(cond)
+			}
+			q.cond = cond
+		}
+		container := reflect.New(typ)
+		num, err := q.All(container.Interface())
+		if err != nil {
+			return err
+		}
+		if num == 0 {
+			return nil
+		}
+		batch := container.Elem()
+		_, last, _ = getExistPk(o.mi, batch.Index(batch.Len()-1).Elem())
+		if err := fn(batch.Interface()); err != nil {
+			return err
+		}
+		if num < int64(size) {
+			return nil
+		}
+	}
+}
+
 // query all data and map to []map[string]interface.
 // expres means condition expression.
 // it converts data to []map[column]value.
 func (o *querySet) Values(results *[]Params, exprs ...string) (int64, error) {
-	return o.orm.alias.DbBaser.ReadValues(o.orm.db, o, o.mi, o.cond, exprs, results, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.ReadValues(o.orm.db, o, o.mi, o.getCond(), exprs, results, o.orm.alias.TZ)
 }
 
 // query all data and map to [][]interface
 // it converts data to [][column_index]value
 func (o *querySet) ValuesList(results *[]ParamsList, exprs ...string) (int64, error) {
-	return o.orm.alias.DbBaser.ReadValues(o.orm.db, o, o.mi, o.cond, exprs, results, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.ReadValues(o.orm.db, o, o.mi, o.getCond(), exprs, results, o.orm.alias.TZ)
 }
 
 // query all data and map to []interface.
 // it's designed for one row record set, auto change to []value, not [][column]value.
 func (o *querySet) ValuesFlat(result *ParamsList, expr string) (int64, error) {
-	return o.orm.alias.DbBaser.ReadValues(o.orm.db, o, o.mi, o.cond, []string{expr}, result, o.orm.alias.TZ)
+	return o.orm.alias.DbBaser.ReadValues(o.orm.db, o, o.mi, o.getCond(), []string{expr}, result, o.orm.alias.TZ)
 }
 
 // query all rows into map[string]interface with specify key and value column name.
@@ -288,7 +676,7 @@ func (o querySet) WithContext(ctx context.Context) QuerySeter {
 // create new QuerySeter.
 func newQuerySet(orm *orm, mi *modelInfo) QuerySeter {
 	o := new(querySet)
-	o.mi = mi
+	o.mi = mi.inSchema(orm.schema)
 	o.orm = orm
 	return o
 }
@@ -16,8 +16,10 @@ package orm
 
 import (
 	"database/sql"
+	sqldriver "database/sql/driver"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -25,17 +27,134 @@ import (
 type rawPrepare struct {
 	rs     *rawSet
 	stmt   stmtQuerier
+	names  []string // named markers in order
+	args   []interface{}
 	closed bool
+	base   *rawPrepare // statement a SetArgs copy was made from, it holds the closed state
+}
+
+// check the statement is closed, by Close of the copy or of its base.
+func (o *rawPrepare) isClosed() bool {
+	if o.base != nil {
+		return o.base.closed
+	}
+	return o.closed
+}
+
+// get the args of the statement, a single map or struct arg binds the named markers.
+func (o *rawPrepare) bindArgs(args []interface{}) ([]interface{}, error) {
+	if len(o.names) > 0 && len(args) == 1 && isNamedArg(args[0]) {
+		values, err := getNamedArgs(o.names, args[0])
+		if err != nil {
+			return nil, err
+		}
+		args = values
+	}
+	return getFlatParams(nil, args, o.rs.orm.alias.TZ), nil
+}
+
+// get a raw set reading the rows of the statement with the args of SetArgs.
+func (o *rawPrepare) rawSet() (*rawSet, error) {
+	if o.isClosed() {
+		return nil, ErrStmtClosed
+	}
+	args, err := o.bindArgs(o.args)
+	if err != nil {
+		return nil, err
+	}
+	rs := *o.rs
+	rs.stmt, rs.args = o.stmt, args
+	return &rs, nil
 }
 
 func (o *rawPrepare) Exec(args ...interface{}) (sql.Result, error) {
-	if o.closed {
+	if o.isClosed() {
 		return nil, ErrStmtClosed
 	}
+	args, err := o.bindArgs(args)
+	if err != nil {
+		return nil, err
+	}
 	return o.stmt.Exec(args...)
 }
 
+// set args for the queries of the statement
+func (o *rawPrepare) SetArgs(args ...interface{}) RawPreparer {
+	p := *o
+	if p.base == nil {
+		p.base = o
+	}
+	p.args = args
+	return &p
+}
+
+// query data and map to container, see RawSeter.QueryRow
+func (o *rawPrepare) QueryRow(containers ...interface{}) error {
+	rs, err := o.rawSet()
+	if err != nil {
+		return err
+	}
+	return rs.QueryRow(containers...)
+}
+
+// query data rows and map to container, see RawSeter.QueryRows
+func (o *rawPrepare) QueryRows(containers ...interface{}) (int64, error) {
+	rs, err := o.rawSet()
+	if err != nil {
+		return 0, err
+	}
+	return rs.QueryRows(containers...)
+}
+
+// query data to []map[string]interface, see RawSeter.Values
+func (o *rawPrepare) Values(container *[]Params, cols ...string) (int64, error) {
+	rs, err := o.rawSet()
+	if err != nil {
+		return 0, err
+	}
+	return rs.Values(container, cols...)
+}
+
+// query data to [][]interface, see RawSeter.ValuesList
+func (o *rawPrepare) ValuesList(container *[]ParamsList, cols ...string) (int64, error) {
+	rs, err := o.rawSet()
+	if err != nil {
+		return 0, err
+	}
+	return rs.ValuesList(container, cols...)
+}
+
+// query data to []interface, see RawSeter.ValuesFlat
+func (o *rawPrepare) ValuesFlat(container *ParamsList, cols ...string) (int64, error) {
+	rs, err := o.rawSet()
+	if err != nil {
+		return 0, err
+	}
+	return rs.ValuesFlat(container, cols...)
+}
+
+// query all rows into map[string]interface with specify key and value column name, see RawSeter.RowsToMap
+func (o *rawPrepare) RowsToMap(result *Params, keyCol, valueCol string) (int64, error) {
+	rs, err := o.rawSet()
+	if err != nil {
+		return 0, err
+	}
+	return rs.RowsToMap(result, keyCol, valueCol)
+}
+
+// query all rows into struct with specify key and value column name, see RawSeter.RowsToStruct
+func (o *rawPrepare) RowsToStruct(ptrStruct interface{}, keyCol, valueCol string) (int64, error) {
+	rs, err := o.rawSet()
+	if err != nil {
+		return 0, err
+	}
+	return rs.RowsToStruct(ptrStruct, keyCol, valueCol)
+}
+
 func (o *rawPrepare) Close() error {
+	if o.base != nil {
+		return o.base.Close()
+	}
 	o.closed = true
 	return o.stmt.Close()
 }
@@ -44,7 +163,17 @@ func newRawPreparer(rs *rawSet) (RawPreparer, error) {
 	o := new(rawPrepare)
 	o.rs = rs
 
-	query := rs.query
+	query, args := rs.query, rs.args
+	// the named markers are only bound when the statement is prepared with a map or struct arg,
+	// other :name and @name, like sqlserver @p1 or mysql variables, are kept.
+	if len(args) == 1 && isNamedArg(args[0]) {
+		if q, names, ok := parseNamedMarks(query); ok && len(names) > 0 {
+			query, o.names = q, names
+			args, _ = getNamedArgs(names, args[0])
+		}
+	}
+	// the markers of slice args given to Raw or SetArgs are expanded for their length
+	query, _ = expandSliceMarks(query, args, rs.orm.alias.TZ)
 	rs.orm.alias.DbBaser.ReplaceMarks(&query)
 
 	st, err := rs.orm.db.Prepare(query)
@@ -64,6 +193,7 @@ type rawSet struct {
 	query string
 	args  []interface{}
 	orm   *orm
+	stmt  stmtQuerier // prepared statement of the query, args are bound
 }
 
 var _ RawSeter = new(rawSet)
@@ -76,11 +206,95 @@ func (o rawSet) SetArgs(args ...interface{}) RawSeter {
 
 // execute raw sql and return sql.Result
 func (o *rawSet) Exec() (sql.Result, error) {
-	query := o.query
+	query, args, err := o.queryArgs()
+	if err != nil {
+		return nil, err
+	}
+	return o.orm.db.Exec(query, args...)
+}
+
+// set the value of a column to a field, a relation gets a model with the value as pk.
+func (o *rawSet) setRawField(field reflect.Value, fi *fieldInfo, value interface{}) {
+	if fi != nil && fi.fieldType&IsRelField > 0 {
+		if field.IsNil() {
+			field.Set(reflect.New(fi.relModelInfo.addrField.Elem().Type()))
+		}
+		field = field.Elem().FieldByIndex(fi.relModelInfo.fields.pk.fieldIndex)
+	}
+	o.setFieldValue(field, value)
+}
+
+// fill the nested structs of ind with the prefixed columns of a row.
+// the elements of child slices are grouped by their pk under the key of the parent.
+func (o *rawSet) setRawNested(ind reflect.Value, node *rawNested, key string, values map[string]interface{}, groups map[string]int) {
+	for _, child := range node.children {
+		if !child.hasValue(values) {
+			continue
+		}
+		field := ind.FieldByIndex(child.index)
+		ckey := key + "\x00" + child.prefix
+		var elem reflect.Value
+		switch {
+		case child.slice:
+			if child.pkCol != "" {
+				ckey += "\x00" + fmt.Sprint(reflect.ValueOf(values[child.pkCol]).Elem().Interface())
+			} else {
+				ckey += "\x00" + fmt.Sprint(field.Len())
+			}
+			i, ok := groups[ckey]
+			if !ok {
+				if child.ptr {
+					field.Set(reflect.Append(field, reflect.New(child.typ)))
+				} else {
+					field.Set(reflect.Append(field, reflect.New(child.typ).Elem()))
+				}
+				i = field.Len() - 1
+				groups[ckey] = i
+			}
+			elem = reflect.Indirect(field.Index(i))
+		case child.ptr:
+			if field.IsNil() {
+				field.Set(reflect.New(child.typ))
+			}
+			elem = field.Elem()
+		default:
+			elem = field
+		}
+		for _, col := range child.cols {
+			o.setRawField(elem.FieldByIndex(col.index), col.fi, reflect.ValueOf(values[col.name]).Elem().Interface())
+		}
+		o.setRawNested(elem, child, ckey, values, groups)
+	}
+}
+
+// get the query with the markers of the driver and its flat args.
+// a single map or struct arg binds the named markers :name and @name of the query.
+func (o *rawSet) queryArgs() (string, []interface{}, error) {
+	query, args := o.query, o.args
+	if len(args) == 1 && isNamedArg(args[0]) {
+		if q, names, ok := parseNamedMarks(query); ok && len(names) > 0 {
+			values, err := getNamedArgs(names, args[0])
+			if err != nil {
+				return "", nil, err
+			}
+			query, args = q, values
+		}
+	}
+	query, args = expandSliceMarks(query, args, o.orm.alias.TZ)
 	o.orm.alias.DbBaser.ReplaceMarks(&query)
+	return query, args, nil
+}
 
-	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
-	return o.orm.db.Exec(query, args...)
+// query the rows, with the prepared statement when the raw set reads one.
+func (o *rawSet) queryRows() (*sql.Rows, error) {
+	if o.stmt != nil {
+		return o.stmt.Query(o.args...)
+	}
+	query, args, err := o.queryArgs()
+	if err != nil {
+		return nil, err
+	}
+	return o.orm.db.Query(query, args...)
 }
 
 // set field value to row container
@@ -295,11 +509,7 @@ func (o *rawSet) QueryRow(containers ...interface{}) error {
 		}
 	}
 
-	query := o.query
-	o.orm.alias.DbBaser.ReplaceMarks(&query)
-
-	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
-	rows, err := o.orm.db.Query(query, args...)
+	rows, err := o.queryRows()
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ErrNoRows
@@ -343,12 +553,7 @@ func (o *rawSet) QueryRow(containers ...interface{}) error {
 					if fi := sMi.fields.GetByColumn(col); fi != nil {
 						value := reflect.ValueOf(columnsMp[col]).Elem().Interface()
 						field := ind.FieldByIndex(fi.fieldIndex)
-						if fi.fieldType&IsRelField > 0 {
-							mf := reflect.New(fi.relModelInfo.addrField.Elem().Type())
-							field.Set(mf)
-							field = mf.Elem().FieldByIndex(fi.relModelInfo.fields.pk.fieldIndex)
-						}
-						o.setFieldValue(field, value)
+						o.setRawField(field, fi, value)
 					}
 				}
 			} else {
@@ -389,6 +594,44 @@ func (o *rawSet) QueryRow(containers ...interface{}) error {
 
 // query data rows and map to container
 func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
+	rows, err := o.queryRows()
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	return o.readRows(rows, containers...)
+}
+
+// query the result sets of the query and map each one to the next container, see QueryRows.
+func (o *rawSet) QueryMulti(containers ...interface{}) (int64, error) {
+	rows, err := o.queryRows()
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	var cnt int64
+	for i, container := range containers {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return cnt, err
+			}
+			return cnt, fmt.Errorf("<RawSeter.QueryMulti> no result set for container %d of %d", i+1, len(containers))
+		}
+		num, err := o.readRows(rows, container)
+		cnt += num
+		if err != nil {
+			return cnt, err
+		}
+	}
+	return cnt, nil
+}
+
+// map the rows of the current result set to the containers.
+func (o *rawSet) readRows(rows *sql.Rows, containers ...interface{}) (int64, error) {
 	var (
 		refs  = make([]interface{}, 0, len(containers))
 		sInds []reflect.Value
@@ -428,18 +671,11 @@ func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
 		}
 	}
 
-	query := o.query
-	o.orm.alias.DbBaser.ReplaceMarks(&query)
-
-	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
-	rows, err := o.orm.db.Query(query, args...)
-	if err != nil {
-		return 0, err
-	}
-
-	defer rows.Close()
-
-	var cnt int64
+	var (
+		cnt    int64
+		nested *rawNested
+		groups map[string]int
+	)
 	nInds := make([]reflect.Value, len(sInds))
 	sInd := sInds[0]
 
@@ -450,6 +686,14 @@ func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
 			if err != nil {
 				return 0, err
 			}
+			if nested == nil {
+				typ := eTyps[0]
+				if typ.Kind() == reflect.Ptr {
+					typ = typ.Elem()
+				}
+				nested = newRawNested(typ, sMi, "", columns)
+				groups = make(map[string]int)
+			}
 
 			columnsMp := make(map[string]interface{}, len(columns))
 
@@ -468,8 +712,17 @@ func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
 				sInd.Set(reflect.New(sInd.Type()).Elem())
 			}
 
+			// rows of the same pk fill one struct when child slices are grouped
+			key, grouped := "", false
+			if nested.grouped() {
+				key = fmt.Sprint(reflect.ValueOf(columnsMp[nested.pkCol]).Elem().Interface())
+				_, grouped = groups[key]
+			}
+
 			var ind reflect.Value
-			if eTyps[0].Kind() == reflect.Ptr {
+			if grouped {
+				ind = reflect.Indirect(sInd.Index(groups[key]))
+			} else if eTyps[0].Kind() == reflect.Ptr {
 				ind = reflect.New(eTyps[0].Elem())
 			} else {
 				ind = reflect.New(eTyps[0])
@@ -484,12 +737,7 @@ func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
 					if fi := sMi.fields.GetByColumn(col); fi != nil {
 						value := reflect.ValueOf(columnsMp[col]).Elem().Interface()
 						field := ind.FieldByIndex(fi.fieldIndex)
-						if fi.fieldType&IsRelField > 0 {
-							mf := reflect.New(fi.relModelInfo.addrField.Elem().Type())
-							field.Set(mf)
-							field = mf.Elem().FieldByIndex(fi.relModelInfo.fields.pk.fieldIndex)
-						}
-						o.setFieldValue(field, value)
+						o.setRawField(field, fi, value)
 					}
 				}
 			} else {
@@ -522,11 +770,20 @@ func (o *rawSet) QueryRows(containers ...interface{}) (int64, error) {
 				recursiveSetField(ind)
 			}
 
+			o.setRawNested(ind, nested, key, columnsMp, groups)
+
+			if grouped {
+				continue
+			}
+
 			if eTyps[0].Kind() == reflect.Ptr {
 				ind = ind.Addr()
 			}
 
 			sInd = reflect.Append(sInd, ind)
+			if nested.grouped() {
+				groups[key] = sInd.Len() - 1
+			}
 
 		} else {
 			if err := rows.Scan(refs...); err != nil {
@@ -573,13 +830,7 @@ func (o *rawSet) readValues(container interface{}, needCols []string) (int64, er
 		panic(fmt.Errorf("<RawSeter> unsupport read values type `%T`", container))
 	}
 
-	query := o.query
-	o.orm.alias.DbBaser.ReplaceMarks(&query)
-
-	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
-
-	var rs *sql.Rows
-	rs, err := o.orm.db.Query(query, args...)
+	rs, err := o.queryRows()
 	if err != nil {
 		return 0, err
 	}
@@ -700,12 +951,7 @@ func (o *rawSet) queryRowsTo(container interface{}, keyCol, valueCol string) (in
 		ind = &id
 	}
 
-	query := o.query
-	o.orm.alias.DbBaser.ReplaceMarks(&query)
-
-	args := getFlatParams(nil, o.args, o.orm.alias.TZ)
-
-	rs, err := o.orm.db.Query(query, args...)
+	rs, err := o.queryRows()
 	if err != nil {
 		return 0, err
 	}
@@ -845,3 +1091,408 @@ func newRawSet(orm *orm, query string, args []interface{}) RawSeter {
 	o.orm = orm
 	return o
 }
+
+// check the arg is a slice or array expanded to a list of markers, []byte is a scalar.
+func isSliceArg(arg interface{}) bool {
+	switch arg.(type) {
+	case []byte, sqldriver.Valuer:
+		return false
+	}
+	kind := reflect.Indirect(reflect.ValueOf(arg)).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// get the indexes of the ? markers of query.
+func getMarkIndexes(query string) []int {
+	var marks []int
+	for i := 0; i < len(query); i++ {
+		if j := skipQuoted(query, i); j > i {
+			i = j - 1
+		} else if query[i] == '?' {
+			marks = append(marks, i)
+		}
+	}
+	return marks
+}
+
+// expand the markers of slice args to one marker per element and flat the args.
+// e.g. "id IN (?)" with []int{1, 2} becomes "id IN (?, ?)", an empty slice becomes "id IN (NULL)".
+// args not matching the markers one to one, like slices already expanded by hand, are only flattened.
+func expandSliceMarks(query string, args []interface{}, tz *time.Location) (string, []interface{}) {
+	marks := getMarkIndexes(query)
+	expand := len(marks) == len(args)
+	if expand {
+		expand = false
+		for _, arg := range args {
+			if isSliceArg(arg) {
+				expand = true
+				break
+			}
+		}
+	}
+	if !expand {
+		return query, getFlatParams(nil, args, tz)
+	}
+
+	var (
+		buf    strings.Builder
+		params = make([]interface{}, 0, len(args))
+		last   int
+	)
+	for i, arg := range args {
+		buf.WriteString(query[last:marks[i]])
+		last = marks[i] + 1
+		values := getFlatParams(nil, []interface{}{arg}, tz)
+		switch {
+		case !isSliceArg(arg):
+			buf.WriteByte('?')
+		case len(values) == 0:
+			buf.WriteString("NULL")
+		default:
+			buf.WriteString(strings.Repeat("?, ", len(values)-1) + "?")
+		}
+		params = append(params, values...)
+	}
+	buf.WriteString(query[last:])
+	return buf.String(), params
+}
+
+// check the arg binds named markers, a map with string keys or a struct.
+func isNamedArg(arg interface{}) bool {
+	switch arg.(type) {
+	case time.Time, *time.Time, Fielder, sqldriver.Valuer:
+		return false
+	}
+	val := reflect.Indirect(reflect.ValueOf(arg))
+	switch val.Kind() {
+	case reflect.Map:
+		return val.Type().Key().Kind() == reflect.String
+	case reflect.Struct:
+		return true
+	}
+	return false
+}
+
+// check c can be in a named marker.
+func isNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// return the end of the quoted string or identifier, or comment starting at query[i].
+// the end is i when query[i] starts none of them.
+func skipQuoted(query string, i int) int {
+	num := len(query)
+	c := query[i]
+	switch {
+	case c == '\'' || c == '"' || c == '`':
+		j := i + 1
+		for j < num {
+			if query[j] == c {
+				if j+1 < num && query[j+1] == c {
+					j += 2
+					continue
+				}
+				return j + 1
+			}
+			j++
+		}
+		return num
+	case c == '-' && i+1 < num && query[i+1] == '-':
+		if j := strings.IndexByte(query[i:], '\n'); j >= 0 {
+			return i + j
+		}
+		return num
+	case c == '/' && i+1 < num && query[i+1] == '*':
+		if j := strings.Index(query[i+2:], "*/"); j >= 0 {
+			return i + 2 + j + 2
+		}
+		return num
+	}
+	return i
+}
+
+// get the names of the mysql variables assigned by @name := in query.
+func getAssignedVars(query string) map[string]bool {
+	vars := make(map[string]bool)
+	num := len(query)
+	for i := 0; i < num; i++ {
+		if j := skipQuoted(query, i); j > i {
+			i = j - 1
+			continue
+		}
+		if query[i] != '@' || i+1 >= num || !isNameChar(query[i+1]) {
+			continue
+		}
+		j := i + 1
+		for j < num && isNameChar(query[j]) {
+			j++
+		}
+		if strings.HasPrefix(strings.TrimLeft(query[j:], " \t\r\n"), ":=") {
+			vars[query[i+1:j]] = true
+		}
+		i = j - 1
+	}
+	return vars
+}
+
+// replace the named markers :name and @name of query by ?.
+// quoted strings and identifiers, comments, casts like ::int, @@vars
+// and the mysql variables assigned by @name := are kept.
+// ok is false when the query also has ? markers.
+func parseNamedMarks(query string) (string, []string, bool) {
+	var (
+		buf        strings.Builder
+		names      []string
+		positional bool
+	)
+	vars := getAssignedVars(query)
+	num := len(query)
+	for i := 0; i < num; i++ {
+		c := query[i]
+		if j := skipQuoted(query, i); j > i {
+			buf.WriteString(query[i:j])
+			i = j - 1
+			continue
+		}
+		switch {
+		case (c == ':' || c == '@') && i+1 < num && isNameChar(query[i+1]) && (query[i+1] < '0' || query[i+1] > '9') &&
+			(i == 0 || query[i-1] != c && !isNameChar(query[i-1])):
+			j := i + 1
+			for j < num && isNameChar(query[j]) {
+				j++
+			}
+			if c == '@' && vars[query[i+1:j]] {
+				buf.WriteString(query[i:j])
+			} else {
+				names = append(names, query[i+1:j])
+				buf.WriteByte('?')
+			}
+			i = j - 1
+		default:
+			if c == '?' {
+				positional = true
+			}
+			buf.WriteByte(c)
+		}
+	}
+	if positional && len(names) > 0 {
+		return query, nil, false
+	}
+	return buf.String(), names, true
+}
+
+// get the values of the named markers from a map or struct, repeated names repeat the value.
+// struct fields are found by field name or column, a registered model also by its orm tags.
+func getNamedArgs(names []string, arg interface{}) ([]interface{}, error) {
+	val := reflect.Indirect(reflect.ValueOf(arg))
+	args := make([]interface{}, 0, len(names))
+
+	if val.Kind() == reflect.Map {
+		for _, name := range names {
+			v := val.MapIndex(reflect.ValueOf(name).Convert(val.Type().Key()))
+			if !v.IsValid() {
+				return nil, fmt.Errorf("<RawSeter> named param `%s` not found", name)
+			}
+			args = append(args, v.Interface())
+		}
+		return args, nil
+	}
+
+	mi, isModel := modelCache.getByFullName(getFullName(val.Type()))
+	for _, name := range names {
+		var field reflect.Value
+		if isModel {
+			if fi, ok := mi.fields.GetByAny(name); ok && fi.dbcol {
+				field = val.FieldByIndex(fi.fieldIndex)
+				if fi.rel {
+					if field.IsNil() {
+						args = append(args, nil)
+						continue
+					}
+					field = reflect.Indirect(field).FieldByIndex(fi.relModelInfo.fields.pk.fieldIndex)
+				}
+			}
+		} else {
+			for _, sf := range reflect.VisibleFields(val.Type()) {
+				if sf.PkgPath != "" || sf.Anonymous {
+					continue
+				}
+				column := getColumnName(0, reflect.Value{}, sf, parseStructTagColumn(sf))
+				if sf.Name == name || column == name {
+					field = val.FieldByIndex(sf.Index)
+					break
+				}
+			}
+		}
+		if !field.IsValid() {
+			return nil, fmt.Errorf("<RawSeter> named param `%s` not found", name)
+		}
+		v := field.Interface()
+		if f, ok := v.(Fielder); ok {
+			v = f.RawValue()
+		}
+		args = append(args, v)
+	}
+	return args, nil
+}
+
+// get the column(...) tag of a struct field.
+func parseStructTagColumn(sf reflect.StructField) string {
+	_, tags := parseStructTag(sf.Tag.Get(defaultStructTagName))
+	return tags["column"]
+}
+
+// RawColumnSep separates the prefixes of the columns mapped to nested structs by RawSeter.QueryRows.
+// e.g. the column "profile.age" sets users[i].Profile.Age, "posts.title" the Title of the users[i].Posts elements.
+var RawColumnSep = "."
+
+// a struct of a raw query row, the root struct or a nested one found by a column prefix.
+type rawNested struct {
+	prefix   string
+	index    []int // index of the field in the parent struct
+	typ      reflect.Type
+	mi       *modelInfo
+	ptr      bool // the field or the slice elements are pointers
+	slice    bool // the field is a slice grouped by pk
+	pkCol    string
+	cols     []rawNestedCol
+	children []*rawNested
+}
+
+// a prefixed column of a nested struct.
+type rawNestedCol struct {
+	name  string
+	index []int
+	fi    *fieldInfo
+}
+
+// build the nested structs of typ from the prefixed columns.
+func newRawNested(typ reflect.Type, mi *modelInfo, prefix string, columns []string) *rawNested {
+	node := &rawNested{prefix: prefix, typ: typ, mi: mi}
+	for _, col := range columns {
+		if !strings.Contains(col, RawColumnSep) {
+			continue
+		}
+		parts := strings.Split(col, RawColumnSep)
+		cur := node
+		for _, name := range parts[:len(parts)-1] {
+			if cur = cur.child(name); cur == nil {
+				break
+			}
+		}
+		if cur == nil {
+			continue
+		}
+		if index, fi := getRawFieldIndex(cur.typ, cur.mi, parts[len(parts)-1], false); index != nil {
+			cur.cols = append(cur.cols, rawNestedCol{name: col, index: index, fi: fi})
+		}
+	}
+	node.setPkCol(columns)
+	return node
+}
+
+// get or add the nested struct of the field called name.
+func (n *rawNested) child(name string) *rawNested {
+	prefix := name
+	if n.prefix != "" {
+		prefix = n.prefix + RawColumnSep + name
+	}
+	for _, child := range n.children {
+		if child.prefix == prefix {
+			return child
+		}
+	}
+
+	index, _ := getRawFieldIndex(n.typ, n.mi, name, true)
+	if index == nil {
+		return nil
+	}
+	child := &rawNested{prefix: prefix, index: index}
+	typ := n.typ.FieldByIndex(index).Type
+	if typ.Kind() == reflect.Slice {
+		child.slice = true
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Ptr {
+		child.ptr = true
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+	child.typ = typ
+	child.mi, _ = modelCache.getByFullName(getFullName(typ))
+	n.children = append(n.children, child)
+	return child
+}
+
+// find the pk column of the struct and its children, the model pk or the id column.
+func (n *rawNested) setPkCol(columns []string) {
+	col := "id"
+	if n.mi != nil && n.mi.fields.pk != nil {
+		col = n.mi.fields.pk.column
+	}
+	if n.prefix != "" {
+		col = n.prefix + RawColumnSep + col
+	}
+	for _, c := range columns {
+		if c == col {
+			n.pkCol = col
+		}
+	}
+	for _, child := range n.children {
+		child.setPkCol(columns)
+	}
+}
+
+// check the rows are grouped by the pk of the root struct, it has slices of children.
+func (n *rawNested) grouped() bool {
+	if n.pkCol == "" {
+		return false
+	}
+	for _, child := range n.children {
+		if child.slice {
+			return true
+		}
+	}
+	return false
+}
+
+// check a column of the struct or its children is not null, a LEFT JOIN without match is null.
+func (n *rawNested) hasValue(values map[string]interface{}) bool {
+	for _, col := range n.cols {
+		if reflect.ValueOf(values[col.name]).Elem().Interface() != nil {
+			return true
+		}
+	}
+	for _, child := range n.children {
+		if child.hasValue(values) {
+			return true
+		}
+	}
+	return false
+}
+
+// get the index of the field of typ called name, found by model field name or column,
+// or by field name, column tag or column name of the name strategy.
+// a relation is only a struct field when isStruct is set.
+func getRawFieldIndex(typ reflect.Type, mi *modelInfo, name string, isStruct bool) ([]int, *fieldInfo) {
+	if mi != nil {
+		if fi, ok := mi.fields.GetByAny(name); ok && (isStruct && fi.rel || isStruct && fi.reverse || !isStruct && fi.dbcol) {
+			if isStruct {
+				return fi.fieldIndex, nil
+			}
+			return fi.fieldIndex, fi
+		}
+		return nil, nil
+	}
+	for _, sf := range reflect.VisibleFields(typ) {
+		if sf.PkgPath != "" || sf.Anonymous {
+			continue
+		}
+		if sf.Name == name || getColumnName(0, reflect.Value{}, sf, parseStructTagColumn(sf)) == name {
+			return sf.Index, nil
+		}
+	}
+	return nil, nil
+}
@@ -12,6 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+//go:build go1.8
 // +build go1.8
 
 package orm
@@ -20,9 +21,12 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math"
+	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -197,6 +201,9 @@ func TestSyncDb(t *testing.T) {
 	RegisterModel(new(IntegerPk))
 	RegisterModel(new(UintPk))
 	RegisterModel(new(PtrPk))
+	RegisterModel(new(DataJSON))
+	RegisterModel(new(TenantItem), new(TenantTag), new(TenantTagItem))
+	RegisterModel(new(DefaultScoped))
 
 	err := RunSyncdb("default", true, Debug)
 	throwFail(t, err)
@@ -221,6 +228,9 @@ func TestRegisterModels(t *testing.T) {
 	RegisterModel(new(IntegerPk))
 	RegisterModel(new(UintPk))
 	RegisterModel(new(PtrPk))
+	RegisterModel(new(DataJSON))
+	RegisterModel(new(TenantItem), new(TenantTag), new(TenantTagItem))
+	RegisterModel(new(DefaultScoped))
 
 	BootStrap()
 
@@ -2296,6 +2306,1392 @@ func TestPtrPk(t *testing.T) {
 	throwFail(t, AssertIs(num, 1))
 }
 
+func TestJSONObjectField(t *testing.T) {
+	d := DataJSON{
+		Address: JSONAddress{City: "Beijing", Street: "Chang'an"},
+		Meta:    map[string]string{"level": "gold"},
+		Tags:    []string{"a", "b"},
+	}
+	id, err := dORM.Insert(&d)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(id, 1))
+
+	r := DataJSON{ID: d.ID}
+	err = dORM.Read(&r)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(r.Address.City, "Beijing"))
+	throwFailNow(t, AssertIs(r.Meta["level"], "gold"))
+	throwFailNow(t, AssertIs(len(r.Tags), 2))
+	throwFailNow(t, AssertIs(r.Extra == nil, true))
+
+	r.Extra = &JSONAddress{City: "Shanghai"}
+	_, err = dORM.Update(&r, "Extra")
+	throwFailNow(t, err)
+
+	var list []*DataJSON
+	num, err := dORM.QueryTable(new(DataJSON)).All(&list)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+	throwFailNow(t, AssertIs(list[0].Extra.City, "Shanghai"))
+	throwFailNow(t, AssertIs(list[0].Tags[1], "b"))
+
+	colTyp := func(driver DriverType, model interface{}, name string) string {
+		mi, _ := modelCache.getByFullName(getFullName(reflect.Indirect(reflect.ValueOf(model)).Type()))
+		fi := mi.fields.GetByName(name)
+		return getColumnTyp(&alias{Driver: driver, DbBaser: dbBasers[driver]}, fi)
+	}
+	// plain string json fields keep varchar outside postgres
+	throwFail(t, AssertIs(colTyp(DRGreenplum, new(Data), "JSON"), "varchar(255)"))
+	throwFail(t, AssertIs(colTyp(DROpengauss, new(Data), "Jsonb"), "varchar(255)"))
+	throwFail(t, AssertIs(colTyp(DRPostgres, new(Data), "Jsonb"), "jsonb"))
+	throwFail(t, AssertIs(colTyp(DRGreenplum, new(DataJSON), "Address"), "json"))
+	throwFail(t, AssertIs(colTyp(DROracle, new(DataJSON), "Address"), "CLOB"))
+	throwFail(t, AssertIs(colTyp(DRDameng, new(DataJSON), "Tags"), "CLOB"))
+	throwFail(t, AssertIs(colTyp(DRMySQL, new(DataJSON), "Meta"), "longtext"))
+}
+
+func TestPaginator(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	var all []*User
+	total, err := qs.OrderBy("-id").All(&all)
+	throwFailNow(t, err)
+
+	var users []*User
+	page, err := NewPaginator(qs, 2).Page(2, &users)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(page.Total, total))
+	throwFailNow(t, AssertIs(page.HasPrev, true))
+
+	p := NewPaginator(qs.OrderBy("-id"), 2)
+	var ids []int
+	var cursors []string
+	cursor := ""
+	for {
+		users = nil
+		page, err = p.Cursor(cursor, &users)
+		throwFailNow(t, err)
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		cursors = append(cursors, page.Prev)
+		if !page.HasNext {
+			break
+		}
+		cursor = page.Next
+	}
+	throwFailNow(t, AssertIs(len(ids), total))
+	for i, u := range all {
+		throwFailNow(t, AssertIs(ids[i], u.ID))
+	}
+
+	if len(cursors) > 1 {
+		users = nil
+		page, err = p.Cursor(cursors[1], &users)
+		throwFailNow(t, err)
+		throwFailNow(t, AssertIs(len(users), 2))
+		throwFailNow(t, AssertIs(users[0].ID, all[0].ID))
+		throwFailNow(t, AssertIs(page.HasPrev, false))
+	}
+
+	_, err = p.Cursor("not a cursor", &users)
+	throwFailNow(t, AssertIs(err, ErrInvalidCursor))
+}
+
+func TestInBatches(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	total, err := qs.Count()
+	throwFailNow(t, err)
+
+	var ids []int
+	err = qs.RelatedSel().InBatches(2, func(batch interface{}) error {
+		users := batch.([]*User)
+		throwFailNow(t, AssertIs(len(users) <= 2, true))
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		return nil
+	})
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(ids), total))
+	for i := 1; i < len(ids); i++ {
+		throwFailNow(t, AssertIs(ids[i] > ids[i-1], true))
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = qs.InBatches(1, func(batch interface{}) error {
+		calls++
+		return stop
+	})
+	throwFailNow(t, AssertIs(err, stop))
+	throwFailNow(t, AssertIs(calls, 1))
+}
+
+func TestOnlyDefer(t *testing.T) {
+	qs := dORM.QueryTable("user").Filter("user_name", "astaxie")
+
+	var user User
+	err := qs.RelatedSel().Only("UserName", "Profile__Age").One(&user)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(user.UserName, "astaxie"))
+	throwFailNow(t, AssertIs(user.Email, ""))
+	throwFailNow(t, AssertIs(user.Profile.Age, 30))
+	throwFailNow(t, AssertIs(user.Profile.Money, 0))
+
+	var users []*User
+	deferred := qs.Defer("Email", "Password")
+	num, err := deferred.All(&users)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+	throwFailNow(t, AssertIs(users[0].Email, ""))
+
+	loaded := deferred.LoadedFields()
+	throwFailNow(t, AssertIs(len(loaded), len(deferred.(*querySet).mi.fields.fieldsDB)-2))
+
+	status := users[0].Status
+	users[0].Status = status + 1
+	_, err = dORM.Update(users[0], loaded...)
+	throwFailNow(t, err)
+
+	u := User{ID: users[0].ID}
+	throwFailNow(t, dORM.Read(&u))
+	throwFailNow(t, AssertIs(u.Status, status+1))
+	throwFailNow(t, AssertIs(u.Email, "astaxie@gmail.com"))
+	throwFailNow(t, AssertIs(u.Password, "password"))
+
+	u.Status = status
+	_, err = dORM.Update(&u, "Status")
+	throwFailNow(t, err)
+
+	_, err = qs.Defer("ID").All(&users)
+	throwFailNow(t, AssertNot(err, nil))
+
+	// without cols the deferred columns keep their stored values
+	throwFailNow(t, AssertIs(len(qs.LoadedFields()), len(loaded)+2))
+	users[0].Status = status + 2
+	num, err = dORM.Update(users[0])
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 1))
+	u = User{ID: users[0].ID}
+	throwFailNow(t, dORM.Read(&u))
+	throwFail(t, AssertIs(u.Status, status+2))
+	throwFail(t, AssertIs(u.Email, "astaxie@gmail.com"))
+	throwFail(t, AssertIs(u.Password, "password"))
+
+	// the marks are per model value, a value read with every field writes them all
+	var rows []User
+	_, err = qs.Defer("Email").All(&rows)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(len(getUnloaded(deferred.(*querySet).mi, reflect.ValueOf(rows[0]))), 1))
+	throwFailNow(t, qs.One(&u))
+	throwFail(t, AssertIs(len(getUnloaded(deferred.(*querySet).mi, reflect.ValueOf(u))), 0))
+	u.Status = status
+	_, err = dORM.Update(&u)
+	throwFailNow(t, err)
+}
+
+func TestPrefetchRelated(t *testing.T) {
+	var posts []*Post
+	num, err := dORM.QueryTable("post").OrderBy("Id").PrefetchRelated("Tags", "User__Profile").All(&posts)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num > 0, true))
+	for _, post := range posts {
+		p := Post{ID: post.ID}
+		throwFailNow(t, dORM.Read(&p))
+		n, err := dORM.LoadRelated(&p, "Tags")
+		throwFailNow(t, err)
+		throwFailNow(t, AssertIs(len(post.Tags), n))
+		for i, tag := range post.Tags {
+			throwFailNow(t, AssertIs(tag.ID, p.Tags[i].ID))
+			throwFailNow(t, AssertIs(tag.Name, p.Tags[i].Name))
+		}
+		if post.User.ID == 3 {
+			throwFailNow(t, AssertIs(post.User.UserName, "astaxie"))
+			throwFailNow(t, AssertIs(post.User.Profile.Age, 30))
+		}
+	}
+
+	var users []User
+	num, err = dORM.QueryTable("user").OrderBy("Id").All(&users)
+	throwFailNow(t, err)
+	_, err = dORM.LoadRelatedMulti(users, "Posts")
+	throwFailNow(t, err)
+	for _, user := range users {
+		u := User{ID: user.ID}
+		n, err := dORM.LoadRelated(&u, "Posts")
+		throwFailNow(t, err)
+		throwFailNow(t, AssertIs(len(user.Posts), n))
+	}
+
+	var tags []*Tag
+	_, err = dORM.QueryTable("tag").All(&tags)
+	throwFailNow(t, err)
+	_, err = dORM.LoadRelatedMulti(&tags, "Posts")
+	throwFailNow(t, err)
+	for _, tag := range tags {
+		tt := Tag{ID: tag.ID}
+		n, err := dORM.LoadRelated(&tt, "Posts")
+		throwFailNow(t, err)
+		throwFailNow(t, AssertIs(len(tag.Posts), n))
+	}
+}
+
+func TestToSQL(t *testing.T) {
+	qs := dORM.QueryTable("user").Filter("user_name", "astaxie")
+
+	query, args, err := qs.ToSQL(OpAll)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(strings.HasPrefix(query, "SELECT "), true))
+	throwFail(t, AssertIs(len(args), 1))
+	throwFail(t, AssertIs(args[0], "astaxie"))
+
+	query, args, err = qs.ToSQL(OpCount)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(strings.Contains(query, "COUNT(*)"), true))
+	throwFail(t, AssertIs(len(args), 1))
+
+	query, args, err = qs.ToSQL(OpUpdate, Params{"status": 3})
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(strings.HasPrefix(query, "UPDATE "), true))
+	throwFail(t, AssertIs(len(args), 2))
+
+	_, _, err = qs.ToSQL(OpUpdate)
+	throwFail(t, AssertIs(err, ErrArgs))
+
+	query, args, err = qs.ToSQL(OpDelete)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(strings.HasPrefix(query, "SELECT "), true))
+	throwFail(t, AssertIs(len(args), 1))
+
+	// nothing is executed
+	var user User
+	throwFailNow(t, qs.One(&user))
+	throwFail(t, AssertIs(user.UserName, "astaxie"))
+
+	if IsSqlite || IsMysql || IsPostgres {
+		plan, err := qs.Explain(false)
+		throwFailNow(t, err)
+		throwFail(t, AssertIs(len(plan) > 0, true))
+	}
+}
+
+func TestRowLock(t *testing.T) {
+	lockSQL := func(base dbBaser, lock *RowLock, of ...*dbTable) string {
+		hint, suffix, err := base.RowLockSQL(lock, of)
+		throwFailNow(t, err)
+		return hint + suffix
+	}
+	mi, _ := modelCache.get("user")
+	t0 := &dbTable{index: "T0", mi: mi}
+
+	pg := newdbBasePostgres()
+	throwFail(t, AssertIs(lockSQL(pg, &RowLock{}), "FOR UPDATE"))
+	throwFail(t, AssertIs(lockSQL(pg, &RowLock{Wait: SkipLocked}), "FOR UPDATE SKIP LOCKED"))
+	throwFail(t, AssertIs(lockSQL(pg, &RowLock{Share: true, Wait: NoWait}), "FOR SHARE NOWAIT"))
+	throwFail(t, AssertIs(lockSQL(pg, &RowLock{}, t0), "FOR UPDATE OF T0"))
+
+	ora := newdbBaseOracle()
+	throwFail(t, AssertIs(lockSQL(ora, &RowLock{Wait: NoWait}, t0), "FOR UPDATE OF T0.id NOWAIT"))
+	_, _, err := ora.RowLockSQL(&RowLock{Share: true}, nil)
+	throwFail(t, AssertNot(err, nil))
+
+	ms := newdbBaseSqlserver()
+	throwFail(t, AssertIs(lockSQL(ms, &RowLock{Wait: SkipLocked}), " WITH (UPDLOCK, ROWLOCK, READPAST)"))
+
+	qs := dORM.QueryTable("user").Filter("user_name", "astaxie")
+	_, _, err = qs.RelatedSel("profile").ForUpdateOf("nothing").ToSQL(OpAll)
+	throwFail(t, AssertNot(err, nil))
+
+	if IsSqlite {
+		var users []*User
+		_, err = qs.ForUpdate(NoWait).All(&users)
+		throwFail(t, AssertNot(err, nil))
+		err = dORM.ReadWithLock(&User{ID: 3}, RowLock{})
+		throwFail(t, AssertNot(err, nil))
+		return
+	}
+
+	query, _, err := qs.RelatedSel("profile").ForUpdateOf("profile").ToSQL(OpAll)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(strings.Contains(query, "OF T1") || strings.Contains(query, "OF T1.id") || strings.Contains(query, "WITH ("), true))
+
+	user := User{ID: 3}
+	throwFail(t, dORM.ReadWithLock(&user, RowLock{}))
+}
+
+func TestUpdateMulti(t *testing.T) {
+	var users []*User
+	num, err := dORM.QueryTable("user").OrderBy("id").All(&users)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num > 1, true))
+
+	old := make([]int16, len(users))
+	for i, user := range users {
+		old[i] = user.Status
+		user.Status = int16(10 + i)
+		user.Nums = 100 + i
+	}
+	num, err = dORM.UpdateMulti(2, users, "Status")
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, len(users)))
+
+	for i, user := range users {
+		u := User{ID: user.ID}
+		throwFailNow(t, dORM.Read(&u))
+		throwFail(t, AssertIs(u.Status, 10+i))
+		throwFail(t, AssertIs(u.Nums == 100+i, false))
+		user.Status = old[i]
+	}
+	num, err = dORM.UpdateMulti(100, &users, "Status")
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, len(users)))
+
+	_, err = dORM.UpdateMulti(2, []*User{})
+	throwFail(t, AssertIs(err, ErrArgs))
+
+	mi, _ := modelCache.get("user")
+	throwFail(t, AssertIs(strings.Join(addAutoNowCols(mi, []string{"Status"}), ","), "Status,Updated"))
+	throwFail(t, AssertIs(strings.Join(addAutoNowCols(mi, []string{"Status", "updated"}), ","), "Status,updated"))
+	num, err = dORM.UpdateMulti(2, users, "Status", "Updated")
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, len(users)))
+
+	al := &alias{Driver: DRPostgres, DbBaser: newdbBasePostgres()}
+	query, args := al.DbBaser.UpdateMultiSQL(mi, []string{"id", "Status"}, [][]interface{}{{1, 2}, {3, 4}}, al, nil)
+	throwFail(t, AssertIs(query, `UPDATE "user" AS T0 SET "Status" = V."Status" FROM (VALUES (CAST($1 AS integer), CAST($2 AS smallint)), (CAST($3 AS integer), CAST($4 AS smallint))) AS V ("id", "Status") WHERE T0."id" = V."id"`))
+	throwFail(t, AssertIs(len(args), 4))
+}
+
+// sqlite with RETURNING disabled to run the emulation
+type dbBaseNoReturning struct {
+	dbBaseSqlite
+}
+
+func (d *dbBaseNoReturning) ReturningSQL(mi *modelInfo, deleted bool) (string, string) {
+	return "", ""
+}
+
+func TestUpdateDeleteReturning(t *testing.T) {
+	if !IsSqlite && !IsPostgres {
+		return
+	}
+	emulated := new(dbBaseNoReturning)
+	emulated.ins = emulated
+	mi, _ := modelCache.get("tag")
+	q := dORM.(*orm).db
+	tz := dORM.(*orm).alias.TZ
+
+	for _, d := range []dbBaser{dORM.(*orm).alias.DbBaser, emulated} {
+		tags := []*Tag{{Name: "returning1"}, {Name: "returning2"}}
+		_, err := dORM.InsertMulti(1, tags)
+		throwFailNow(t, err)
+
+		qs := dORM.QueryTable("tag").Filter("name__startswith", "returning").(*querySet)
+
+		var updated []*Tag
+		num, err := d.UpdateBatchReturning(q, qs, mi, qs.cond, Params{"name": "returning-x"}, &updated, tz)
+		throwFailNow(t, err)
+		throwFailNow(t, AssertIs(num, 2))
+		throwFailNow(t, AssertIs(len(updated), 2))
+		for _, tag := range updated {
+			throwFail(t, AssertIs(tag.Name, "returning-x"))
+			throwFail(t, AssertIs(tag.ID == tags[0].ID || tag.ID == tags[1].ID, true))
+		}
+
+		var deleted []Tag
+		num, err = d.DeleteBatchReturning(q, qs, mi, qs.cond, &deleted, tz)
+		throwFailNow(t, err)
+		throwFailNow(t, AssertIs(num, 2))
+		throwFailNow(t, AssertIs(len(deleted), 2))
+		throwFail(t, AssertIs(deleted[0].Name, "returning-x"))
+		throwFail(t, AssertIs(qs.Exist(), false))
+	}
+
+	_, err := dORM.Insert(&Tag{Name: "returning3"})
+	throwFailNow(t, err)
+	qs := dORM.QueryTable("tag").Filter("name", "returning3")
+	var tags []*Tag
+	num, err := qs.UpdateReturning(Params{"name": "returning4"}, &tags)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(tags[0].Name, "returning4"))
+	num, err = dORM.QueryTable("tag").Filter("name", "returning4").DeleteReturning(&tags)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(tags[0].Name, "returning4"))
+}
+
+func TestScope(t *testing.T) {
+	qs := dORM.QueryTable("user")
+	staff, err := qs.Filter("is_staff", true).Count()
+	throwFailNow(t, err)
+
+	num, err := qs.Scope("staff").Count()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, staff))
+
+	var users []*User
+	num, err = qs.Scope("recent").Exclude("user_name", "nobody").All(&users)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num > 1, true))
+	for i := 1; i < len(users); i++ {
+		throwFail(t, AssertIs(users[i].ID < users[i-1].ID, true))
+	}
+
+	active, err := qs.Filter("is_active", true).Filter("is_staff", true).Count()
+	throwFailNow(t, err)
+	var staffs []*User
+	num, err = qs.Filter("is_active", true).Scope("staff", "recent").All(&staffs)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, active))
+	for _, user := range staffs {
+		throwFail(t, AssertIs(user.IsStaff, true))
+		throwFail(t, AssertIs(user.IsActive, true))
+	}
+
+	num, err = qs.SetCond(NewCondition().And("user_name", "astaxie")).Scope("staff").Count()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num <= 1, true))
+
+	assert := func() {
+		throwFail(t, AssertNot(recover(), nil))
+	}
+	func() {
+		defer assert()
+		qs.Scope("nothing")
+	}()
+}
+
+func TestDefaultOrderingCond(t *testing.T) {
+	mi, ok := modelCache.get("default_scoped")
+	throwFailNow(t, AssertIs(ok, true))
+	throwFailNow(t, AssertIs(len(mi.ordering), 1))
+	throwFailNow(t, AssertNot(mi.defCond, nil))
+
+	items := []*DefaultScoped{
+		{Name: "a", IsActive: true},
+		{Name: "b"},
+		{Name: "c", IsActive: true},
+		{Name: "d", IsActive: true},
+	}
+	_, err := dORM.InsertMulti(len(items), items)
+	throwFailNow(t, err)
+
+	qs := dORM.QueryTable("default_scoped")
+	num, err := qs.Count()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 3))
+
+	var rows []*DefaultScoped
+	num, err = qs.All(&rows)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 3))
+	throwFail(t, AssertIs(rows[0].Name, "d"))
+	throwFail(t, AssertIs(rows[2].Name, "a"))
+	for _, row := range rows {
+		throwFail(t, AssertIs(row.IsActive, true))
+	}
+
+	num, err = qs.OrderBy("id").Filter("id__gt", 0).All(&rows)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 3))
+	throwFail(t, AssertIs(rows[0].Name, "a"))
+	throwFail(t, AssertIs(rows[2].Name, "d"))
+
+	num, err = qs.Unscoped().All(&rows)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 4))
+
+	num, err = qs.Unscoped().Filter("id__gt", 0).Delete()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 4))
+}
+
+func TestTenant(t *testing.T) {
+	o1 := dORM.WithTenant(1)
+	o2 := dORM.WithTenant(2)
+
+	item := TenantItem{Name: "one"}
+	_, err := o1.Insert(&item)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(item.TenantID, 1))
+
+	items := []*TenantItem{{Name: "two"}, {Name: "three"}}
+	_, err = o2.InsertMulti(2, items)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(items[1].TenantID, 2))
+	_, err = o2.QueryTable("tenant_item").OrderBy("id").All(&items)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(items), 2))
+
+	_, err = o1.Insert(&TenantItem{TenantID: 2, Name: "cross"})
+	throwFail(t, AssertIs(err, ErrTenant))
+
+	num, err := o1.QueryTable("tenant_item").Count()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	num, err = o2.QueryTable("tenant_item").Unscoped().Count()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 2))
+	num, err = dORM.QueryTable("tenant_item").Count()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 3))
+
+	// rows of another tenant are not found
+	other := TenantItem{ID: items[0].ID}
+	throwFail(t, AssertIs(o1.Read(&other), ErrNoRows))
+	other = TenantItem{ID: items[0].ID}
+	throwFailNow(t, o2.Read(&other))
+	throwFail(t, AssertIs(other.Name, "two"))
+
+	other.TenantID = 0
+	other.Name = "hijack"
+	num, err = o1.Update(&other)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 0))
+	num, err = o1.Delete(&TenantItem{ID: items[0].ID})
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 0))
+	_, err = o1.Update(items[0])
+	throwFail(t, AssertIs(err, ErrTenant))
+
+	items[0].Name = "two-2"
+	_, err = o1.UpdateMulti(2, []*TenantItem{{ID: item.ID, Name: "one-2"}, {ID: items[0].ID, Name: "two-2"}}, "Name")
+	throwFail(t, AssertIs(err, ErrTenant))
+
+	// the batched statement itself is limited to the tenant
+	o := o1.(*orm)
+	mi, _ := o.getMiInd(&TenantItem{}, true)
+	num, err = o.alias.DbBaser.UpdateMulti(o.db, mi, reflect.ValueOf([]*TenantItem{{ID: items[0].ID, Name: "x"}, {ID: items[1].ID, Name: "x"}}), 2, o.alias, []string{"Name"}, o.tenant)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 0))
+	other = TenantItem{ID: items[0].ID}
+	throwFailNow(t, o2.Read(&other))
+	throwFail(t, AssertIs(other.Name, "two"))
+
+	_, err = o2.QueryTable("tenant_item").Filter("name", "two").Update(Params{"TenantID": 1})
+	throwFail(t, AssertIs(err, ErrTenant))
+	num, err = o1.QueryTable("tenant_item").Filter("name__startswith", "t").Update(Params{"name": "x"})
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 0))
+
+	// tenant 2 cannot upsert the row of tenant 1
+	_, err = o2.InsertOrUpdate(&TenantItem{ID: item.ID, Name: "stolen"}, "id")
+	throwFail(t, AssertIs(err, ErrTenantUpsert))
+	other = TenantItem{ID: item.ID}
+	throwFailNow(t, o1.Read(&other))
+	throwFail(t, AssertIs(other.Name, item.Name))
+
+	// a plain Ormer can move rows between tenants
+	other.TenantID = 2
+	num, err = dORM.Update(&other)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(o1.Read(&TenantItem{ID: item.ID}), ErrNoRows))
+	other.TenantID = 1
+	num, err = dORM.Update(&other)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	// m2m links need both ends in the tenant
+	tag := TenantTag{Name: "tag"}
+	_, err = o1.Insert(&tag)
+	throwFailNow(t, err)
+	m2m := o1.QueryM2M(&tag, "Items")
+	// the related models are only checked, not changed
+	linked := TenantItem{ID: item.ID}
+	num, err = m2m.Add(&linked)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(linked.TenantID, 0))
+	_, err = m2m.Add(&TenantItem{ID: item.ID, TenantID: 2})
+	throwFail(t, AssertIs(err, ErrTenant))
+	_, err = m2m.Add(items[0].ID)
+	throwFail(t, AssertIs(err, ErrTenant))
+	_, err = o2.QueryM2M(&TenantTag{ID: tag.ID}, "Items").Add(items[0].ID)
+	throwFail(t, AssertIs(err, ErrTenant))
+	_, err = o2.QueryM2M(&TenantTag{ID: tag.ID}, "Items").Remove(item.ID)
+	throwFail(t, AssertIs(err, ErrTenant))
+	// a model of another tenant does not panic, every query fails
+	other2m := o2.QueryM2M(&TenantTag{ID: tag.ID, TenantID: 1}, "Items")
+	_, err = other2m.Count()
+	throwFail(t, AssertIs(err, ErrTenant))
+	_, err = other2m.Clear()
+	throwFail(t, AssertIs(err, ErrTenant))
+	throwFail(t, AssertIs(other2m.Exist(&item), false))
+	num, err = m2m.Count()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	num, err = m2m.Remove(&item)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+	num, err = o1.Delete(&tag)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	num, err = o2.QueryTable("tenant_item").Filter("id__gt", 0).Delete()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 2))
+	num, err = o1.Delete(&item)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+}
+
+func TestUsingSchema(t *testing.T) {
+	Q := dDbBaser.TableQuote()
+	inSchema := func(table string) string {
+		return Q + "tenant_42" + Q + "." + Q + table + Q
+	}
+
+	o := dORM.UsingSchema("tenant_42")
+	query, _, err := o.QueryTable("user").RelatedSel("profile").Filter("user_name", "astaxie").ToSQL(OpAll)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(strings.Contains(query, inSchema("user")+" T0"), true))
+	throwFail(t, AssertIs(strings.Contains(query, inSchema("user_profile")+" T1"), true))
+
+	// the original Ormer is unchanged
+	query, _, err = dORM.QueryTable("user").RelatedSel("profile").ToSQL(OpAll)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(strings.Contains(query, "tenant_42"), false))
+
+	// models resolved by the Ormer share one copy per schema
+	mi, _ := o.(*orm).getMiInd(&User{}, true)
+	umi, _ := modelCache.getByFullName(mi.fullName)
+	throwFail(t, AssertIs(mi.schema, "tenant_42"))
+	throwFail(t, AssertIs(mi == umi.inSchema("tenant_42"), true))
+	throwFail(t, AssertIs(mi.inSchema("") == umi, true))
+
+	al := getDbAlias("default")
+	sqls, indexes := getDbCreateSQL(al, "tenant_42")
+	throwFail(t, AssertIs(len(sqls) > 0, true))
+	var found bool
+	for _, sql := range sqls {
+		if strings.Contains(sql, inSchema("user")+" (") {
+			found = true
+		}
+	}
+	throwFail(t, AssertIs(found, true))
+	for _, idx := range indexes["user"] {
+		throwFail(t, AssertIs(strings.Contains(idx.SQL, inSchema("user")), true))
+	}
+	for _, sql := range getDbDropSQL(al, "tenant_42") {
+		throwFail(t, AssertIs(strings.Contains(sql, Q+"tenant_42"+Q+"."), true))
+	}
+
+	query, err = dDbBaser.CreateSchemaSQL("tenant_42")
+	switch al.Driver {
+	case DRPostgres, DROpengauss, DRGreenplum, DRSqlserver:
+		throwFailNow(t, err)
+		throwFail(t, AssertIs(strings.Contains(query, "tenant_42"), true))
+	default:
+		throwFail(t, AssertNot(err, nil))
+	}
+
+	if IsSqlite {
+		// an attached database is a schema, one connection keeps it attached
+		db, err := sql.Open("sqlite3", ":memory:")
+		throwFailNow(t, err)
+		defer db.Close()
+		db.SetMaxOpenConns(1)
+		so, err := NewOrmWithDB("sqlite3", "schema_sqlite", db)
+		throwFailNow(t, err)
+		_, err = so.Raw("ATTACH DATABASE ':memory:' AS tenant_42").Exec()
+		throwFailNow(t, err)
+		for _, sql := range sqls {
+			if strings.Contains(sql, inSchema("user")+" (") || strings.Contains(sql, inSchema("user_profile")+" (") {
+				_, err = so.Raw(sql).Exec()
+				throwFailNow(t, err)
+			}
+		}
+
+		so = so.UsingSchema("tenant_42")
+		profile := Profile{Age: 42}
+		_, err = so.Insert(&profile)
+		throwFailNow(t, err)
+		_, err = so.Insert(&User{UserName: "schema", Profile: &profile})
+		throwFailNow(t, err)
+
+		var user User
+		err = so.QueryTable("user").RelatedSel("profile").Filter("user_name", "schema").One(&user)
+		throwFailNow(t, err)
+		throwFailNow(t, AssertNot(user.Profile, nil))
+		throwFail(t, AssertIs(user.Profile.Age, 42))
+		throwFailNow(t, AssertNot(user.Profile.User, nil))
+		throwFail(t, AssertIs(user.Profile.User.UserName, "schema"))
+	}
+}
+
+func TestGenerics(t *testing.T) {
+	users, err := Query[User](dORM).Filter("user_name", "slene").All()
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(len(users), 1))
+	throwFail(t, AssertIs(users[0].UserName, "slene"))
+
+	user, err := Query[User](dORM).Filter("user_name", "astaxie").One("ID", "UserName")
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(user.UserName, "astaxie"))
+	throwFail(t, AssertIs(user.Email, ""))
+
+	_, err = Query[User](dORM).Filter("user_name", "nothing").One()
+	throwFail(t, AssertIs(err, ErrNoRows))
+
+	num, err := Query[User](dORM).Filter("user_name__in", "slene", "astaxie").Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	// the model info is resolved once per type
+	mi, ok := modelCache.cacheByType.Load(reflect.TypeOf(User{}))
+	throwFailNow(t, AssertIs(ok, true))
+	throwFail(t, AssertIs(mi.(*modelInfo).fullName, getFullName(reflect.TypeOf(User{}))))
+
+	user, err = Get[User](dORM, users[0].ID)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(user.UserName, "slene"))
+	user, err = Get[User](dORM, int64(users[0].ID))
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(user.UserName, "slene"))
+	_, err = Get[User](dORM, 1<<30)
+	throwFail(t, AssertIs(err, ErrNoRows))
+	_, err = Get[User](dORM, "1")
+	throwFail(t, AssertNot(err, nil))
+
+	tags := []*Tag{{Name: "generic1"}, {Name: "generic2"}}
+	num, err = InsertAll(dORM, 1, tags)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(tags[0].ID > 0, true))
+
+	var batches int
+	err = Query[Tag](dORM).Filter("name__startswith", "generic").InBatches(1, func(batch []*Tag) error {
+		batches++
+		return nil
+	})
+	throwFail(t, err)
+	throwFail(t, AssertIs(batches, 2))
+
+	num, err = Query[Tag](dORM).Filter("name__startswith", "generic").Delete()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+
+	num, err = InsertAll[Tag](dORM, 1, nil)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 0))
+}
+
+func TestFieldPath(t *testing.T) {
+	age := FieldPath("Profile").Join("Age")
+	throwFail(t, AssertIs(age.String(), "Profile__Age"))
+	throwFail(t, AssertIs(FieldPath("").Join("Age").String(), "Age"))
+	throwFail(t, AssertIs(age.Desc(), "-Profile__Age"))
+
+	cond := age.Gt(18)
+	throwFailNow(t, AssertIs(len(cond.params), 1))
+	throwFail(t, AssertIs(strings.Join(cond.params[0].exprs, ExprSep), "Profile__Age__gt"))
+	throwFail(t, AssertIs(cond.params[0].args[0], 18))
+
+	cond = FieldPath("UserName").Eq("slene")
+	throwFail(t, AssertIs(strings.Join(cond.params[0].exprs, ExprSep), "UserName"))
+
+	num, err := dORM.QueryTable("user").SetCond(FieldPath("profile").Join("age").Gte(30)).Count()
+	throwFail(t, err)
+	want, _ := dORM.QueryTable("user").Filter("profile__age__gte", 30).Count()
+	throwFail(t, AssertIs(num, want))
+
+	num, err = dORM.QueryTable("user").SetCond(FieldPath("user_name").In("slene", "astaxie")).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+}
+
+func TestParseFilter(t *testing.T) {
+	values, _ := url.ParseQuery("user_name__in=slene,astaxie&status__gte=1&order=-profile__age,id&limit=5&offset=0")
+	fq, err := ParseFilterValues("user", values, nil)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(strings.Join(fq.Orders, ","), "-Profile__Age,ID"))
+	throwFail(t, AssertIs(fq.Limit, 5))
+
+	var users []*User
+	num, err := fq.Apply(dORM.QueryTable("user")).All(&users)
+	throwFailNow(t, err)
+	want, _ := dORM.QueryTable("user").Filter("user_name__in", "slene", "astaxie").Filter("status__gte", 1).Count()
+	throwFail(t, AssertIs(num, want))
+
+	// values are converted to the field types
+	fq, err = ParseFilterValues(&User{}, url.Values{"profile__age__between": {"1,100"}, "is_staff": {"false"}}, nil)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(fq.Cond.params[0].args[0], false))
+	throwFail(t, AssertIs(fq.Cond.params[1].args[0], int64(1)))
+
+	for _, query := range []string{
+		"password=x",      // not in the allow-list
+		"unknown=1",       // unknown field
+		"status__regex=1", // unknown operator
+		"status=abc",      // not a number
+		"created__gt=yesterday",
+		"profile__age__between=1",
+		"order=email",
+		"limit=-1",
+		"limit=a",
+	} {
+		values, _ := url.ParseQuery(query)
+		_, err := ParseFilterValues("user", values, nil)
+		throwFail(t, AssertNot(err, nil))
+	}
+
+	_, err = ParseFilterValues("user", url.Values{"limit": {"101"}}, &FilterOptions{Fields: []string{"id"}, MaxLimit: 100})
+	throwFail(t, AssertNot(err, nil))
+	_, err = ParseFilterValues("user", url.Values{"user_name": {"slene"}}, &FilterOptions{Fields: []string{"id"}})
+	throwFail(t, AssertNot(err, nil))
+	_, err = ParseFilterValues("profile", url.Values{"age": {"1"}}, nil)
+	throwFail(t, AssertNot(err, nil))
+
+	fq, err = ParseFilterJSON("user", []byte(`{"filter": {"user_name": "slene", "id__in": [1, 2, 3], "created__gte": "2000-01-01"}, "order": "-id", "limit": 10}`), nil)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(len(fq.Cond.params), 3))
+	throwFail(t, AssertIs(strings.Join(fq.Orders, ","), "-ID"))
+	throwFail(t, AssertIs(fq.Limit, 10))
+	num, err = fq.Apply(dORM.QueryTable("user")).Count()
+	throwFail(t, err)
+	want, _ = dORM.QueryTable("user").Filter("user_name", "slene").Filter("id__in", 1, 2, 3).Count()
+	throwFail(t, AssertIs(num, want))
+
+	for _, doc := range []string{
+		`{"filter": {"status": [1, 2]}}`,
+		`{"filter": {"user_name": 1}}`,
+		`{"filter": {"is_staff": "yes"}}`,
+		`{"where": {}}`,
+		`{"order": 1}`,
+		`[]`,
+	} {
+		_, err := ParseFilterJSON("user", []byte(doc), nil)
+		throwFail(t, AssertNot(err, nil))
+	}
+}
+
+func TestConditionJSON(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sub := NewCondition().And("user_name", "slene").Or("profile__age__between", 18, 30)
+	cond := NewCondition().And("status__gte", 1).
+		AndNotCond(sub).
+		Or("id__in", []int{1, 2}).
+		And("created__lt", created).
+		AndNot("profile__isnull", true).
+		Raw("id", "IN (SELECT 1)")
+
+	throwFail(t, AssertIs(cond.String(), `status >= 1 AND NOT (user_name = "slene" OR profile__age BETWEEN 18 AND 30)`+
+		` OR id IN (1, 2) AND created < "2024-01-02T03:04:05Z" AND NOT profile IS NULL AND id IN (SELECT 1)`))
+	throwFail(t, AssertIs(NewCondition().And("email__icontains", "@").String(), `email ICONTAINS "@"`))
+
+	data, err := json.Marshal(cond)
+	throwFailNow(t, err)
+
+	var decoded Condition
+	throwFailNow(t, json.Unmarshal(data, &decoded))
+	throwFail(t, AssertIs(decoded.String(), cond.String()))
+	throwFailNow(t, AssertIs(len(decoded.params), 6))
+	throwFail(t, AssertIs(decoded.params[0].args[0], int64(1)))
+	throwFail(t, AssertIs(decoded.params[1].isCond && decoded.params[1].isNot, true))
+	throwFail(t, AssertIs(decoded.params[2].isOr, true))
+	throwFail(t, AssertIs(decoded.params[3].args[0].(time.Time).Equal(created), true))
+	throwFail(t, AssertIs(decoded.params[5].isRaw, true))
+
+	again, err := json.Marshal(&decoded)
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(string(again), string(data)))
+
+	// models are encoded as their pk
+	data, err = json.Marshal(NewCondition().And("user", &User{ID: 3}))
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(string(data), `[{"expr":"user","args":[3]}]`))
+
+	// a replayed condition queries the same rows
+	cond = NewCondition().And("user_name__in", "slene", "astaxie").AndNot("status", 0)
+	data, err = json.Marshal(cond)
+	throwFailNow(t, err)
+	var replay *Condition
+	throwFailNow(t, json.Unmarshal(data, &replay))
+	num, err := dORM.QueryTable("user").SetCond(replay).Count()
+	throwFail(t, err)
+	want, _ := dORM.QueryTable("user").SetCond(cond).Count()
+	throwFail(t, AssertIs(num, want))
+
+	for _, doc := range []string{
+		`{}`,
+		`[{"expr":"id"}]`,
+		`[{"args":[1]}]`,
+		`[{"raw":"IN (1)"}]`,
+		`[{"expr":"id","args":[{"x":1}]}]`,
+		`[{"expr":"id","args":[1],"cond":[]}]`,
+	} {
+		throwFail(t, AssertNot(json.Unmarshal([]byte(doc), new(Condition)), nil))
+	}
+}
+
+func TestFilterByExample(t *testing.T) {
+	qs := dORM.QueryTable("user")
+
+	num, err := qs.FilterByExample(&User{UserName: "slene"}, nil).Count()
+	throwFailNow(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	cond := qs.FilterByExample(&User{UserName: "sle", Status: 3}, &ExampleOptions{
+		Operators: map[string]string{"user_name": "istartswith"},
+		Include:   []string{"IsStaff"},
+	}).GetCond()
+	throwFailNow(t, AssertIs(len(cond.params), 3))
+	throwFail(t, AssertIs(cond.String(), `UserName ISTARTSWITH "sle" AND Status = 3 AND IsStaff = false`))
+
+	var user User
+	throwFailNow(t, qs.Filter("user_name", "slene").RelatedSel().One(&user))
+	num, err = qs.FilterByExample(&User{UserName: "sle", Status: user.Status}, &ExampleOptions{
+		Operators: map[string]string{"UserName": "istartswith"},
+	}).Count()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	// relations filter by the pk of the related model
+	if user.Profile != nil {
+		num, err = qs.FilterByExample(&User{Profile: &Profile{ID: user.Profile.ID}}, nil).Count()
+		throwFail(t, err)
+		throwFail(t, AssertIs(num, 1))
+	}
+	cond = qs.FilterByExample(&User{}, &ExampleOptions{Include: []string{"profile"}}).GetCond()
+	throwFail(t, AssertIs(cond.String(), `Profile IS NULL`))
+
+	// range bounds
+	cond = qs.FilterByExample(&User{ID: 1}, &ExampleOptions{RangeTo: &User{ID: 3, Nums: 5}}).GetCond()
+	throwFail(t, AssertIs(cond.String(), `ID BETWEEN 1 AND 3 AND Nums <= 5`))
+	num, err = qs.FilterByExample(&User{ID: 1}, &ExampleOptions{RangeTo: &User{ID: 3}}).Count()
+	throwFail(t, err)
+	want, _ := qs.Filter("id__between", 1, 3).Count()
+	throwFail(t, AssertIs(num, want))
+
+	assert := func() {
+		throwFail(t, AssertNot(recover(), nil))
+	}
+	func() {
+		defer assert()
+		qs.FilterByExample(&Profile{}, nil)
+	}()
+	func() {
+		defer assert()
+		qs.FilterByExample(&User{}, &ExampleOptions{Operators: map[string]string{"UserName": "in"}})
+	}()
+	func() {
+		defer assert()
+		qs.FilterByExample(&User{}, &ExampleOptions{Include: []string{"Posts"}})
+	}()
+}
+
+func TestRawNamedParams(t *testing.T) {
+	query, names, ok := parseNamedMarks("SELECT a::int, @@b, ':c', \"@d\" FROM t WHERE x = :x AND y = @y -- :z\n OR x = :x /* @w */")
+	throwFail(t, AssertIs(ok, true))
+	throwFail(t, AssertIs(query, "SELECT a::int, @@b, ':c', \"@d\" FROM t WHERE x = ? AND y = ? -- :z\n OR x = ? /* @w */"))
+	throwFail(t, AssertIs(len(names), 3))
+	throwFail(t, AssertIs(names[0], "x"))
+	throwFail(t, AssertIs(names[1], "y"))
+	throwFail(t, AssertIs(names[2], "x"))
+
+	_, _, ok = parseNamedMarks("SELECT * FROM t WHERE x = :x AND y = ?")
+	throwFail(t, AssertIs(ok, false))
+
+	query, names, ok = parseNamedMarks("SELECT @rank := @rank + 1, @n:=0 FROM t WHERE x = :x OR y = @y")
+	throwFail(t, AssertIs(ok, true))
+	throwFail(t, AssertIs(query, "SELECT @rank := @rank + 1, @n:=0 FROM t WHERE x = ? OR y = ?"))
+	throwFail(t, AssertIs(strings.Join(names, ","), "x,y"))
+
+	Q := dDbBaser.TableQuote()
+	query = fmt.Sprintf("SELECT %sid%s FROM %suser%s WHERE %suser_name%s = :name OR %semail%s = :name OR %sid%s = @id ORDER BY %sid%s", Q, Q, Q, Q, Q, Q, Q, Q, Q, Q, Q, Q)
+
+	var ids []int
+	num, err := dORM.Raw(query, map[string]interface{}{"name": "slene", "id": 3}).QueryRows(&ids)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(ids[0], 2))
+	throwFail(t, AssertIs(ids[1], 3))
+
+	var id int
+	err = dORM.Raw(fmt.Sprintf("SELECT %sid%s FROM %suser%s WHERE %suser_name%s = :user_name OR %sid%s = :ID ORDER BY %sid%s", Q, Q, Q, Q, Q, Q, Q, Q, Q, Q),
+		&User{UserName: "astaxie", ID: 4}).QueryRow(&id)
+	throwFail(t, err)
+	throwFail(t, AssertIs(id, 3))
+
+	type userArgs struct {
+		Name string
+		UID  int `orm:"column(id)"`
+	}
+	err = dORM.Raw(query, userArgs{Name: "nobody", UID: 0}).QueryRow(&id)
+	throwFail(t, err)
+	throwFail(t, AssertIs(id, 4))
+
+	_, err = dORM.Raw(query, map[string]interface{}{"name": "slene"}).Exec()
+	throwFail(t, AssertNot(err, nil))
+
+	var nums int
+	selectNums := fmt.Sprintf("SELECT %snums%s FROM %suser%s WHERE %sid%s = ?", Q, Q, Q, Q, Q, Q)
+	throwFail(t, dORM.Raw(selectNums, 2).QueryRow(&nums))
+
+	if IsSqlite {
+		// without a map or struct arg the markers are left to the database
+		pre, err := dORM.Raw(query).Prepare()
+		throwFailNow(t, err)
+		throwFail(t, AssertIs(len(pre.(*rawPrepare).names), 0))
+		throwFail(t, pre.Close())
+	}
+
+	pre, err := dORM.Raw(fmt.Sprintf("UPDATE %suser%s SET %snums%s = :nums WHERE %sid%s = :id", Q, Q, Q, Q, Q, Q), Params{}).Prepare()
+	throwFailNow(t, err)
+	res, err := pre.Exec(map[string]interface{}{"nums": nums + 7, "id": 2})
+	throwFail(t, err)
+	num, err = res.RowsAffected()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+
+	throwFail(t, dORM.Raw(selectNums, 2).QueryRow(&id))
+	throwFail(t, AssertIs(id, nums+7))
+
+	_, err = pre.Exec(map[string]interface{}{"nums": nums, "id": 2})
+	throwFail(t, err)
+	throwFail(t, pre.Close())
+}
+
+func TestRawSliceArgs(t *testing.T) {
+	query, args := expandSliceMarks("SELECT * FROM t WHERE a IN (?) AND b = ? AND c = '?' AND d IN (?)", []interface{}{[]int{1, 2}, []byte("x"), []string{}}, time.UTC)
+	throwFail(t, AssertIs(query, "SELECT * FROM t WHERE a IN (?, ?) AND b = ? AND c = '?' AND d IN (NULL)"))
+	throwFail(t, AssertIs(len(args), 3))
+	throwFail(t, AssertIs(args[1], int64(2)))
+	throwFail(t, AssertIs(string(args[2].([]byte)), "x"))
+
+	// a driver.Valuer slice is one value
+	query, args = expandSliceMarks("id IN (?) AND tags = ?", []interface{}{[]int{1, 2}, ValuerStrings{"a", "b"}}, time.UTC)
+	throwFail(t, AssertIs(query, "id IN (?, ?) AND tags = ?"))
+	throwFailNow(t, AssertIs(len(args), 3))
+	throwFail(t, AssertIs(args[2], ValuerStrings{"a", "b"}))
+	query, args = expandSliceMarks("tags = ?", []interface{}{ValuerStrings{"a", "b"}}, time.UTC)
+	throwFail(t, AssertIs(query, "tags = ?"))
+	throwFail(t, AssertIs(len(args), 1))
+
+	// expanded by hand
+	query, args = expandSliceMarks("a IN (?, ?)", []interface{}{[2]int{1, 2}}, time.UTC)
+	throwFail(t, AssertIs(query, "a IN (?, ?)"))
+	throwFail(t, AssertIs(len(args), 2))
+
+	Q := dDbBaser.TableQuote()
+	query = fmt.Sprintf("SELECT %sid%s FROM %suser%s WHERE %sid%s IN (?) AND %suser_name%s <> ? ORDER BY %sid%s", Q, Q, Q, Q, Q, Q, Q, Q, Q, Q)
+
+	var ids []int
+	num, err := dORM.Raw(query, []int{2, 3, 4}, "astaxie").QueryRows(&ids)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(ids[0], 2))
+	throwFail(t, AssertIs(ids[1], 4))
+
+	num, err = dORM.Raw(query).SetArgs([]int{}, "astaxie").QueryRows(&ids)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 0))
+
+	var names []string
+	num, err = dORM.Raw(fmt.Sprintf("SELECT %suser_name%s FROM %suser%s WHERE %sid%s IN (:ids) ORDER BY %sid%s", Q, Q, Q, Q, Q, Q, Q, Q),
+		map[string]interface{}{"ids": []int{2, 3}}).QueryRows(&names)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(names[1], "astaxie"))
+
+	pre, err := dORM.Raw(fmt.Sprintf("UPDATE %suser%s SET %snums%s = %snums%s WHERE %sid%s IN (?)", Q, Q, Q, Q, Q, Q, Q, Q)).SetArgs([]int{0, 0}).Prepare()
+	throwFailNow(t, err)
+	res, err := pre.Exec([]int{2, 3})
+	throwFail(t, err)
+	num, err = res.RowsAffected()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	throwFail(t, pre.Close())
+
+	// driver.Valuer structs are args too
+	pre, err = dORM.Raw(fmt.Sprintf("UPDATE %suser%s SET %snums%s = %snums%s WHERE %suser_name%s = ?", Q, Q, Q, Q, Q, Q, Q, Q)).Prepare()
+	throwFailNow(t, err)
+	res, err = pre.Exec(sql.NullString{String: "slene", Valid: true})
+	throwFailNow(t, err)
+	num, err = res.RowsAffected()
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	var id int
+	throwFail(t, dORM.Raw(fmt.Sprintf("SELECT %sid%s FROM %suser%s WHERE %suser_name%s = ?", Q, Q, Q, Q, Q, Q), sql.NullString{String: "astaxie", Valid: true}).QueryRow(&id))
+	throwFail(t, AssertIs(id, 3))
+	throwFail(t, pre.Close())
+}
+
+func TestRawPrepareQuery(t *testing.T) {
+	Q := dDbBaser.TableQuote()
+	pre, err := dORM.Raw(fmt.Sprintf("SELECT %sid%s, %suser_name%s FROM %suser%s WHERE %sid%s >= ? AND %sid%s <= 4 ORDER BY %sid%s", Q, Q, Q, Q, Q, Q, Q, Q, Q, Q, Q, Q)).Prepare()
+	throwFailNow(t, err)
+
+	var user User
+	throwFail(t, pre.SetArgs(3).QueryRow(&user))
+	throwFail(t, AssertIs(user.UserName, "astaxie"))
+	throwFail(t, pre.SetArgs(4).QueryRow(&user))
+	throwFail(t, AssertIs(user.UserName, "nobody"))
+	throwFail(t, AssertIs(pre.SetArgs(100).QueryRow(&user), ErrNoRows))
+
+	var ids []int
+	var names []string
+	num, err := pre.SetArgs(2).QueryRows(&ids, &names)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+	throwFail(t, AssertIs(names[0], "slene"))
+
+	var maps []Params
+	num, err = pre.SetArgs(3).Values(&maps)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(maps[1]["user_name"], "nobody"))
+
+	var lists []ParamsList
+	num, err = pre.SetArgs(4).ValuesList(&lists)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 1))
+	throwFail(t, AssertIs(lists[0][1], "nobody"))
+
+	var flat ParamsList
+	num, err = pre.SetArgs(3).ValuesFlat(&flat, "user_name")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(flat[0], "astaxie"))
+
+	res := make(Params)
+	num, err = pre.SetArgs(2).RowsToMap(&res, "user_name", "id")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+	throwFail(t, AssertIs(res["astaxie"], "3"))
+
+	st := new(struct {
+		Slene  int
+		Nobody int
+	})
+	num, err = pre.SetArgs(2).RowsToStruct(st, "user_name", "id")
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 3))
+	throwFail(t, AssertIs(st.Nobody, 4))
+
+	p := pre.SetArgs(3)
+	throwFail(t, pre.Close())
+	throwFail(t, AssertIs(pre.SetArgs(2).QueryRow(&user), ErrStmtClosed))
+	throwFail(t, AssertIs(p.QueryRow(&user), ErrStmtClosed))
+	_, err = p.SetArgs(4).Exec()
+	throwFail(t, AssertIs(err, ErrStmtClosed))
+}
+
+func TestRawNestedQueryRows(t *testing.T) {
+	profile := &Profile{Age: 40}
+	_, err := dORM.Insert(profile)
+	throwFailNow(t, err)
+	users := []*User{{UserName: "nested1", Profile: profile}, {UserName: "nested2"}}
+	for _, user := range users {
+		_, err = dORM.Insert(user)
+		throwFailNow(t, err)
+	}
+	posts := []*Post{{User: users[0], Title: "first"}, {User: users[0], Title: "second"}, {User: users[1], Title: "third"}}
+	for _, post := range posts {
+		_, err = dORM.Insert(post)
+		throwFailNow(t, err)
+	}
+	defer func() {
+		for _, post := range posts {
+			dORM.Delete(post)
+		}
+		for _, user := range users {
+			dORM.Delete(user)
+		}
+		dORM.Delete(profile)
+	}()
+
+	Q := dDbBaser.TableQuote()
+	q := func(name string) string { return Q + name + Q }
+	query := fmt.Sprintf("SELECT u.%s, u.%s, pr.%s AS %s, pr.%s AS %s, p.%s AS %s, p.%s AS %s FROM %s u LEFT JOIN %s pr ON pr.%s = u.%s JOIN %s p ON p.%s = u.%s WHERE u.%s IN (?, ?) ORDER BY u.%s, p.%s",
+		q("id"), q("user_name"), q("id"), q("profile.id"), q("age"), q("profile.age"), q("id"), q("posts.id"), q("title"), q("posts.title"),
+		q("user"), q("user_profile"), q("id"), q("profile_id"), q("post"), q("user_id"), q("id"), q("id"), q("id"), q("id"))
+
+	var list []*User
+	num, err := dORM.Raw(query, users[0].ID, users[1].ID).QueryRows(&list)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(list[0].UserName, "nested1"))
+	throwFailNow(t, AssertNot(list[0].Profile == nil, true))
+	throwFail(t, AssertIs(list[0].Profile.ID, profile.ID))
+	throwFail(t, AssertIs(list[0].Profile.Age, 40))
+	throwFailNow(t, AssertIs(len(list[0].Posts), 2))
+	throwFail(t, AssertIs(list[0].Posts[0].Title, "first"))
+	throwFail(t, AssertIs(list[0].Posts[1].Title, "second"))
+	throwFail(t, AssertIs(list[1].Profile == nil, true))
+	throwFailNow(t, AssertIs(len(list[1].Posts), 1))
+	throwFail(t, AssertIs(list[1].Posts[0].ID, posts[2].ID))
+
+	type userPost struct {
+		Title string
+	}
+	type userPosts struct {
+		ID       int
+		UserName string
+		Posts    []userPost
+	}
+	var plain []userPosts
+	num, err = dORM.Raw(query, users[0].ID, users[1].ID).QueryRows(&plain)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(plain[1].UserName, "nested2"))
+	throwFailNow(t, AssertIs(len(plain[0].Posts), 2))
+	throwFail(t, AssertIs(plain[0].Posts[1].Title, "second"))
+
+	// without a slice every row is a struct
+	var rows []*Post
+	num, err = dORM.Raw(fmt.Sprintf("SELECT p.%s, p.%s, u.%s AS %s, u.%s AS %s FROM %s p JOIN %s u ON u.%s = p.%s WHERE u.%s = ? ORDER BY p.%s",
+		q("id"), q("title"), q("id"), q("user.id"), q("user_name"), q("user.user_name"), q("post"), q("user"), q("id"), q("user_id"), q("id"), q("id")), users[0].ID).QueryRows(&rows)
+	throwFailNow(t, err)
+	throwFailNow(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(rows[1].Title, "second"))
+	throwFail(t, AssertIs(rows[1].User.UserName, "nested1"))
+	throwFail(t, AssertIs(rows[1].User.ID, users[0].ID))
+}
+
+func TestRawQueryMulti(t *testing.T) {
+	Q := dDbBaser.TableQuote()
+	query := fmt.Sprintf("SELECT %sid%s, %suser_name%s FROM %suser%s WHERE %sid%s IN (?, ?) ORDER BY %sid%s", Q, Q, Q, Q, Q, Q, Q, Q, Q, Q)
+
+	var users []*User
+	num, err := dORM.Raw(query, 2, 3).QueryMulti(&users)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	throwFailNow(t, AssertIs(len(users), 2))
+	throwFail(t, AssertIs(users[1].UserName, "astaxie"))
+
+	if IsSqlite {
+		// go-sqlite3 returns a single result set
+		var names []string
+		users = nil
+		query = fmt.Sprintf("SELECT %suser_name%s FROM %suser%s WHERE %sid%s = 2; SELECT %sname%s FROM %stag%s", Q, Q, Q, Q, Q, Q, Q, Q, Q, Q)
+		_, err = dORM.Raw(query).QueryMulti(&users, &names)
+		throwFail(t, AssertNot(err, nil))
+		throwFail(t, AssertIs(len(names), 0))
+	}
+
+	// postgres returns every result set of a query without args,
+	// mysql when the connection allows multiple statements
+	if IsPostgres || IsMysql && strings.Contains(DBARGS.Source, "multiStatements=true") {
+		tags, err := dORM.QueryTable("tag").Count()
+		throwFailNow(t, err)
+
+		var names []string
+		users = nil
+		query = fmt.Sprintf("SELECT %sid%s, %suser_name%s FROM %suser%s WHERE %sid%s IN (2, 3) ORDER BY %sid%s; SELECT %sname%s FROM %stag%s", Q, Q, Q, Q, Q, Q, Q, Q, Q, Q, Q, Q, Q, Q)
+		num, err = dORM.Raw(query).QueryMulti(&users, &names)
+		throwFailNow(t, err)
+		throwFail(t, AssertIs(num, 2+tags))
+		throwFailNow(t, AssertIs(len(users), 2))
+		throwFail(t, AssertIs(users[1].UserName, "astaxie"))
+		throwFail(t, AssertIs(len(names), tags))
+
+		_, err = dORM.Raw(query).QueryMulti(&users, &names, &names)
+		throwFail(t, AssertNot(err, nil))
+	}
+}
+
+func TestQueryBuilderArgs(t *testing.T) {
+	build := func(driver string) (string, []interface{}) {
+		qb, err := NewQueryBuilder(driver)
+		throwFailNow(t, err)
+		return qb.Select("id").From("user").Where("age > ?", 18).And("id").InArgs([]int{2, 3}).
+			Or("name = ?", "slene").And("status").InArgs(1, 2).Build()
+	}
+
+	query, args := build("mysql")
+	throwFail(t, AssertIs(query, "SELECT id FROM user WHERE age > ? AND id IN ( ?, ? ) OR name = ? AND status IN ( ?, ? )"))
+	throwFail(t, AssertIs(len(args), 6))
+	throwFail(t, AssertIs(args[1], 2))
+	throwFail(t, AssertIs(args[3], "slene"))
+
+	query, _ = build("postgres")
+	throwFail(t, AssertIs(query, "SELECT id FROM user WHERE age > $1 AND id IN ( $2, $3 ) OR name = $4 AND status IN ( $5, $6 )"))
+	query, _ = build("oracle")
+	throwFail(t, AssertIs(query, "SELECT id FROM user WHERE age > :1 AND id IN ( :2, :3 ) OR name = :4 AND status IN ( :5, :6 )"))
+
+	qb, _ := NewQueryBuilder("tidb")
+	query, args = qb.InsertInto("tag", "name").ValuesArgs("go").Build()
+	throwFail(t, AssertIs(query, "INSERT INTO tag ( name ) VALUES ( ? )"))
+	throwFail(t, AssertIs(args[0], "go"))
+
+	qb, _ = NewQueryBuilder("mysql")
+	query, args = qb.Select("id").From("user").Where("id").InArgs([]int{}).Build()
+	throwFail(t, AssertIs(query, "SELECT id FROM user WHERE id IN ( NULL )"))
+	throwFail(t, AssertIs(len(args), 0))
+
+	// no args at all
+	for name := range drivers {
+		qb, _ = NewQueryBuilder(name)
+		query, args = qb.Select("id").From("user").Where("id").InArgs().Build()
+		throwFail(t, AssertIs(strings.Contains(query, "WHERE id IN ( NULL )"), true), name)
+		throwFail(t, AssertIs(len(args), 0))
+	}
+}
+
+func TestQueryBuilderDrivers(t *testing.T) {
+	for name, typ := range drivers {
+		qb, err := NewQueryBuilder(name)
+		throwFail(t, err)
+		qb2, err := NewQueryBuilder(typ)
+		throwFail(t, err)
+		throwFail(t, AssertIs(fmt.Sprintf("%T", qb), fmt.Sprintf("%T", qb2)))
+	}
+	qb, err := NewQueryBuilder("default")
+	throwFail(t, err)
+	qb2, _ := NewQueryBuilder(dORM.Driver().Type())
+	throwFail(t, AssertIs(fmt.Sprintf("%T", qb), fmt.Sprintf("%T", qb2)))
+	_, err = NewQueryBuilder("unknown")
+	throwFail(t, AssertNot(err, nil))
+
+	sel := func(typ DriverType) string {
+		qb, err := NewQueryBuilder(typ)
+		throwFailNow(t, err)
+		return qb.Select(qb.Quote("u.id")).From("user u").Where("age > ?", 18).OrderBy("id").Limit(10).Offset(20).ForUpdate().String()
+	}
+	throwFail(t, AssertIs(sel(DRSqlite), "SELECT `u`.`id` FROM user u WHERE age > ? ORDER BY id LIMIT 10 OFFSET 20"))
+	throwFail(t, AssertIs(sel(DRTaos), "SELECT `u`.`id` FROM user u WHERE age > ? ORDER BY id LIMIT 10 OFFSET 20"))
+	throwFail(t, AssertIs(sel(DRDameng), `SELECT "u"."id" FROM user u WHERE age > ? ORDER BY id LIMIT 10 OFFSET 20 FOR UPDATE`))
+	throwFail(t, AssertIs(sel(DRGreenplum), `SELECT "u"."id" FROM user u WHERE age > ? ORDER BY id LIMIT 10 OFFSET 20 FOR UPDATE`))
+	throwFail(t, AssertIs(sel(DROpengauss), `SELECT "u"."id" FROM user u WHERE age > ? ORDER BY id LIMIT 10 OFFSET 20 FOR UPDATE`))
+	throwFail(t, AssertIs(sel(DRSqlserver), "SELECT [u].[id] FROM user u WITH (UPDLOCK, ROWLOCK) WHERE age > ? ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"))
+
+	qb, _ = NewQueryBuilder(DRSqlserver)
+	throwFail(t, AssertIs(qb.Select("u.id").From("user u", "post p").Where("p.user_id = u.id").ForUpdate().String(),
+		"SELECT u.id FROM user u WITH (UPDLOCK, ROWLOCK), post p WITH (UPDLOCK, ROWLOCK) WHERE p.user_id = u.id"))
+
+	for _, typ := range []DriverType{DRGreenplum, DROpengauss} {
+		qb, _ = NewQueryBuilder(typ)
+		query, args := qb.Select("id").From("user").Where("age > ?", 18).And("id").InArgs(1, 2).Build()
+		throwFail(t, AssertIs(query, "SELECT id FROM user WHERE age > $1 AND id IN ( $2, $3 )"))
+		throwFail(t, AssertIs(len(args), 3))
+	}
+
+	qb, _ = NewQueryBuilder(DRSqlite)
+	throwFail(t, AssertIs(qb.Select("id").From("user").Offset(5).String(), "SELECT id FROM user LIMIT -1 OFFSET 5"))
+	qb, _ = NewQueryBuilder(DRSqlserver)
+	query, args := qb.Select("id").From("user").Where("id").InArgs([]int{1, 2}).Limit(5).Build()
+	throwFail(t, AssertIs(query, "SELECT id FROM user WHERE id IN ( @p1, @p2 ) ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY"))
+	throwFail(t, AssertIs(len(args), 2))
+	qb, _ = NewQueryBuilder(DRDameng)
+	query, _ = qb.Select("id").From("user").Where("id = ?", 1).Build()
+	throwFail(t, AssertIs(query, "SELECT id FROM user WHERE id = :1"))
+	qb, _ = NewQueryBuilder(DROpengauss)
+	query, _ = qb.Select("id").From("user").Where("id = ?", 1).Build()
+	throwFail(t, AssertIs(query, "SELECT id FROM user WHERE id = $1"))
+
+	Q := dDbBaser.TableQuote()
+	qb, _ = NewQueryBuilder("default")
+	query, args = qb.Select(Q + "user_name" + Q).From(Q + "user" + Q).Where(Q + "id" + Q).InArgs([]int{2, 3}).OrderBy(Q + "id" + Q).Build()
+	var names []string
+	num, err := dORM.Raw(query, args...).QueryRows(&names)
+	throwFail(t, err)
+	throwFail(t, AssertIs(num, 2))
+	throwFail(t, AssertIs(names[0], "slene"))
+}
+
 func TestSnake(t *testing.T) {
 	cases := map[string]string{
 		"i":           "i",
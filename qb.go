@@ -14,7 +14,11 @@
 
 package orm
 
-import "errors"
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
 
 // QueryBuilder is the Query builder interface
 type QueryBuilder interface {
@@ -24,41 +28,153 @@ type QueryBuilder interface {
 	InnerJoin(table string) QueryBuilder
 	LeftJoin(table string) QueryBuilder
 	RightJoin(table string) QueryBuilder
-	On(cond string) QueryBuilder
-	Where(cond string) QueryBuilder
-	And(cond string) QueryBuilder
-	Or(cond string) QueryBuilder
+	On(cond string, args ...interface{}) QueryBuilder
+	Where(cond string, args ...interface{}) QueryBuilder
+	And(cond string, args ...interface{}) QueryBuilder
+	Or(cond string, args ...interface{}) QueryBuilder
 	In(vals ...string) QueryBuilder
+	InArgs(args ...interface{}) QueryBuilder
 	OrderBy(fields ...string) QueryBuilder
 	Asc() QueryBuilder
 	Desc() QueryBuilder
 	Limit(limit int) QueryBuilder
 	Offset(offset int) QueryBuilder
 	GroupBy(fields ...string) QueryBuilder
-	Having(cond string) QueryBuilder
+	Having(cond string, args ...interface{}) QueryBuilder
 	Update(tables ...string) QueryBuilder
 	Set(kv ...string) QueryBuilder
 	Delete(tables ...string) QueryBuilder
 	InsertInto(table string, fields ...string) QueryBuilder
 	Values(vals ...string) QueryBuilder
+	ValuesArgs(args ...interface{}) QueryBuilder
 	Subquery(sub string, alias string) string
+	// Quote return the identifier quoted for the driver, e.g. `user`.`id` in mysql
+	Quote(name string) string
 	String() string
+	// Build return the SQL with the markers of the driver and the args of the conditions,
+	// a slice arg of a ? marker is expanded to a marker per element.
+	// for example:
+	//	qb.Select("id").From("user").Where("age > ?", 18).And("id").InArgs([]int{1, 2})
+	//	query, args := qb.Build() // SELECT id FROM user WHERE age > $1 AND id IN ( $2, $3 ) in postgres
+	//	o.Raw(query, args...).QueryRows(&ids)
+	Build() (string, []interface{})
 }
 
-// NewQueryBuilder return the QueryBuilder
-func NewQueryBuilder(driver string) (qb QueryBuilder, err error) {
-	if driver == "mysql" {
+// NewQueryBuilder return the QueryBuilder of a DriverType, a database alias name or a driver name.
+// for example:
+//
+//	qb, err := NewQueryBuilder(DRPostgres)
+//	qb, err := NewQueryBuilder("default")
+//	qb, err := NewQueryBuilder(o.Driver().Type())
+func NewQueryBuilder(driver interface{}) (qb QueryBuilder, err error) {
+	var t DriverType
+	switch d := driver.(type) {
+	case DriverType:
+		t = d
+	case string:
+		if al, ok := dataBaseCache.get(d); ok {
+			t = al.Driver
+		} else if dr, ok := drivers[d]; ok {
+			t = dr
+		} else if d == "sqlite" {
+			t = DRSqlite
+		}
+	}
+	switch t {
+	case DRMySQL:
 		qb = new(MySQLQueryBuilder)
-	} else if driver == "tidb" {
+	case DRTiDB:
 		qb = new(TiDBQueryBuilder)
-	} else if driver == "postgres" {
+	case DRPostgres:
 		qb = new(PgSQLQueryBuilder)
-	} else if driver == "sqlite" {
-		err = errors.New("sqlite query builder is not supported yet")
-	} else if driver == "godror" || driver == "oracle" {
+	case DRSqlite:
+		qb = new(SQLiteQueryBuilder)
+	case DROracle:
 		qb = new(OracleQueryBuilder)
-	} else {
+	case DRSqlserver:
+		qb = new(SQLServerQueryBuilder)
+	case DRGreenplum:
+		qb = new(GreenplumQueryBuilder)
+	case DRDameng:
+		qb = new(DamengQueryBuilder)
+	case DRTaos:
+		qb = new(TDengineQueryBuilder)
+	case DROpengauss:
+		qb = new(OpenGaussQueryBuilder)
+	default:
 		err = errors.New("unknown driver for query builder")
 	}
 	return
 }
+
+// check the tokens have the token.
+func containsToken(tokens []string, token string) bool {
+	for _, t := range tokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+// quote each part of the dotted name with open and close, close in a part is doubled.
+// * is not quoted.
+func quoteQueryBuilderName(name, open, close string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part != "*" {
+			parts[i] = open + strings.ReplaceAll(part, close, close+close) + close
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// get n ? markers joined by CommaSpace.
+func getQueryBuilderMarks(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strings.Repeat("?"+CommaSpace, n-1) + "?"
+}
+
+// get the markers of InArgs, IN (NULL) matches no row when there are no args.
+func getQueryBuilderInMarks(n int) string {
+	if n == 0 {
+		return "NULL"
+	}
+	return getQueryBuilderMarks(n)
+}
+
+// expand the markers of slice args to a marker per element, an empty slice is NULL.
+// the args are kept as they are when they do not match the markers one to one.
+func expandQueryBuilderArgs(query string, args []interface{}) (string, []interface{}) {
+	marks := getMarkIndexes(query)
+	if len(marks) != len(args) {
+		return query, args
+	}
+	var (
+		buf    strings.Builder
+		values = make([]interface{}, 0, len(args))
+		last   int
+	)
+	for i, arg := range args {
+		buf.WriteString(query[last:marks[i]])
+		last = marks[i] + 1
+		if !isSliceArg(arg) {
+			buf.WriteByte('?')
+			values = append(values, arg)
+			continue
+		}
+		val := reflect.Indirect(reflect.ValueOf(arg))
+		if val.Len() == 0 {
+			buf.WriteString("NULL")
+			continue
+		}
+		buf.WriteString(getQueryBuilderMarks(val.Len()))
+		for j := 0; j < val.Len(); j++ {
+			values = append(values, val.Index(j).Interface())
+		}
+	}
+	buf.WriteString(query[last:])
+	return buf.String(), values
+}
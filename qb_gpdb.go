@@ -0,0 +1,32 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+// GreenplumQueryBuilder is the SQL build of Greenplum, the same as PostgreSQL but for its markers
+type GreenplumQueryBuilder struct {
+	PgSQLQueryBuilder
+}
+
+// Build return the SQL with the markers of the driver and its args
+func (qb *GreenplumQueryBuilder) Build() (string, []interface{}) {
+	query, args := expandQueryBuilderArgs(qb.String(), qb.Args)
+	new(dbBaseGpdb).ReplaceMarks(&query)
+	return query, args
+}
+
+// Quote quote the identifier, each part of a dotted name is quoted
+func (qb *GreenplumQueryBuilder) Quote(name string) string {
+	return quoteQueryBuilderName(name, `"`, `"`)
+}
@@ -26,6 +26,7 @@ const CommaSpace = ", "
 // MySQLQueryBuilder is the SQL build
 type MySQLQueryBuilder struct {
 	Tokens []string
+	Args   []interface{}
 }
 
 // Select will join the fields
@@ -65,26 +66,30 @@ func (qb *MySQLQueryBuilder) RightJoin(table string) QueryBuilder {
 }
 
 // On join with on cond
-func (qb *MySQLQueryBuilder) On(cond string) QueryBuilder {
+func (qb *MySQLQueryBuilder) On(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "ON", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // Where join the Where cond
-func (qb *MySQLQueryBuilder) Where(cond string) QueryBuilder {
+func (qb *MySQLQueryBuilder) Where(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "WHERE", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // And join the and cond
-func (qb *MySQLQueryBuilder) And(cond string) QueryBuilder {
+func (qb *MySQLQueryBuilder) And(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "AND", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // Or join the or cond
-func (qb *MySQLQueryBuilder) Or(cond string) QueryBuilder {
+func (qb *MySQLQueryBuilder) Or(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "OR", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
@@ -94,6 +99,13 @@ func (qb *MySQLQueryBuilder) In(vals ...string) QueryBuilder {
 	return qb
 }
 
+// InArgs join the IN (?, ?) of the args, IN (NULL) without args, a slice arg is expanded by Build
+func (qb *MySQLQueryBuilder) InArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "IN", "(", getQueryBuilderInMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
 // OrderBy join the Order by fields
 func (qb *MySQLQueryBuilder) OrderBy(fields ...string) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "ORDER BY", strings.Join(fields, CommaSpace))
@@ -131,8 +143,9 @@ func (qb *MySQLQueryBuilder) GroupBy(fields ...string) QueryBuilder {
 }
 
 // Having join the Having cond
-func (qb *MySQLQueryBuilder) Having(cond string) QueryBuilder {
+func (qb *MySQLQueryBuilder) Having(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "HAVING", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
@@ -174,6 +187,13 @@ func (qb *MySQLQueryBuilder) Values(vals ...string) QueryBuilder {
 	return qb
 }
 
+// ValuesArgs join the VALUES (?, ?) of the args
+func (qb *MySQLQueryBuilder) ValuesArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "VALUES", "(", getQueryBuilderMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
 // Subquery join the sub as alias
 func (qb *MySQLQueryBuilder) Subquery(sub string, alias string) string {
 	return fmt.Sprintf("(%s) AS %s", sub, alias)
@@ -183,3 +203,13 @@ func (qb *MySQLQueryBuilder) Subquery(sub string, alias string) string {
 func (qb *MySQLQueryBuilder) String() string {
 	return strings.Join(qb.Tokens, " ")
 }
+
+// Build return the SQL and its args
+func (qb *MySQLQueryBuilder) Build() (string, []interface{}) {
+	return expandQueryBuilderArgs(qb.String(), qb.Args)
+}
+
+// Quote quote the identifier, each part of a dotted name is quoted
+func (qb *MySQLQueryBuilder) Quote(name string) string {
+	return quoteQueryBuilderName(name, "`", "`")
+}
@@ -23,6 +23,7 @@ import (
 // OracleQueryBuilder is the SQL build
 type OracleQueryBuilder struct {
 	Tokens []string
+	Args   []interface{}
 }
 
 // Select will join the fields
@@ -62,26 +63,30 @@ func (qb *OracleQueryBuilder) RightJoin(table string) QueryBuilder {
 }
 
 // On join with on cond
-func (qb *OracleQueryBuilder) On(cond string) QueryBuilder {
+func (qb *OracleQueryBuilder) On(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "ON", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // Where join the Where cond
-func (qb *OracleQueryBuilder) Where(cond string) QueryBuilder {
+func (qb *OracleQueryBuilder) Where(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "WHERE", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // And join the and cond
-func (qb *OracleQueryBuilder) And(cond string) QueryBuilder {
+func (qb *OracleQueryBuilder) And(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "AND", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // Or join the or cond
-func (qb *OracleQueryBuilder) Or(cond string) QueryBuilder {
+func (qb *OracleQueryBuilder) Or(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "OR", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
@@ -91,6 +96,13 @@ func (qb *OracleQueryBuilder) In(vals ...string) QueryBuilder {
 	return qb
 }
 
+// InArgs join the IN (?, ?) of the args, IN (NULL) without args, a slice arg is expanded by Build
+func (qb *OracleQueryBuilder) InArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "IN", "(", getQueryBuilderInMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
 // OrderBy join the Order by fields
 func (qb *OracleQueryBuilder) OrderBy(fields ...string) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "ORDER BY", strings.Join(fields, CommaSpace))
@@ -128,8 +140,9 @@ func (qb *OracleQueryBuilder) GroupBy(fields ...string) QueryBuilder {
 }
 
 // Having join the Having cond
-func (qb *OracleQueryBuilder) Having(cond string) QueryBuilder {
+func (qb *OracleQueryBuilder) Having(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "HAVING", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
@@ -171,6 +184,13 @@ func (qb *OracleQueryBuilder) Values(vals ...string) QueryBuilder {
 	return qb
 }
 
+// ValuesArgs join the VALUES (?, ?) of the args
+func (qb *OracleQueryBuilder) ValuesArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "VALUES", "(", getQueryBuilderMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
 // Subquery join the sub as alias
 func (qb *OracleQueryBuilder) Subquery(sub string, alias string) string {
 	return fmt.Sprintf("(%s) AS %s", sub, alias)
@@ -180,3 +200,15 @@ func (qb *OracleQueryBuilder) Subquery(sub string, alias string) string {
 func (qb *OracleQueryBuilder) String() string {
 	return strings.Join(qb.Tokens, " ")
 }
+
+// Build return the SQL with the markers of the driver and its args
+func (qb *OracleQueryBuilder) Build() (string, []interface{}) {
+	query, args := expandQueryBuilderArgs(qb.String(), qb.Args)
+	new(dbBaseOracle).ReplaceMarks(&query)
+	return query, args
+}
+
+// Quote quote the identifier, each part of a dotted name is quoted
+func (qb *OracleQueryBuilder) Quote(name string) string {
+	return quoteQueryBuilderName(name, `"`, `"`)
+}
@@ -9,6 +9,7 @@ import (
 // PgSQLQueryBuilder is the SQL build
 type PgSQLQueryBuilder struct {
 	Tokens []string
+	Args   []interface{}
 }
 
 // Select will join the fields
@@ -48,26 +49,30 @@ func (qb *PgSQLQueryBuilder) RightJoin(table string) QueryBuilder {
 }
 
 // On join with on cond
-func (qb *PgSQLQueryBuilder) On(cond string) QueryBuilder {
+func (qb *PgSQLQueryBuilder) On(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "ON", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // Where join the Where cond
-func (qb *PgSQLQueryBuilder) Where(cond string) QueryBuilder {
+func (qb *PgSQLQueryBuilder) Where(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "WHERE", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // And join the and cond
-func (qb *PgSQLQueryBuilder) And(cond string) QueryBuilder {
+func (qb *PgSQLQueryBuilder) And(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "AND", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // Or join the or cond
-func (qb *PgSQLQueryBuilder) Or(cond string) QueryBuilder {
+func (qb *PgSQLQueryBuilder) Or(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "OR", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
@@ -77,6 +82,13 @@ func (qb *PgSQLQueryBuilder) In(vals ...string) QueryBuilder {
 	return qb
 }
 
+// InArgs join the IN (?, ?) of the args, IN (NULL) without args, a slice arg is expanded by Build
+func (qb *PgSQLQueryBuilder) InArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "IN", "(", getQueryBuilderInMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
 // OrderBy join the Order by fields
 func (qb *PgSQLQueryBuilder) OrderBy(fields ...string) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "ORDER BY", strings.Join(fields, CommaSpace))
@@ -114,8 +126,9 @@ func (qb *PgSQLQueryBuilder) GroupBy(fields ...string) QueryBuilder {
 }
 
 // Having join the Having cond
-func (qb *PgSQLQueryBuilder) Having(cond string) QueryBuilder {
+func (qb *PgSQLQueryBuilder) Having(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "HAVING", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
@@ -157,6 +170,13 @@ func (qb *PgSQLQueryBuilder) Values(vals ...string) QueryBuilder {
 	return qb
 }
 
+// ValuesArgs join the VALUES (?, ?) of the args
+func (qb *PgSQLQueryBuilder) ValuesArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "VALUES", "(", getQueryBuilderMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
 // Subquery join the sub as alias
 func (qb *PgSQLQueryBuilder) Subquery(sub string, alias string) string {
 	return fmt.Sprintf("(%s) AS %s", sub, alias)
@@ -166,3 +186,15 @@ func (qb *PgSQLQueryBuilder) Subquery(sub string, alias string) string {
 func (qb *PgSQLQueryBuilder) String() string {
 	return strings.Join(qb.Tokens, " ")
 }
+
+// Build return the SQL with the markers of the driver and its args
+func (qb *PgSQLQueryBuilder) Build() (string, []interface{}) {
+	query, args := expandQueryBuilderArgs(qb.String(), qb.Args)
+	new(dbBasePostgres).ReplaceMarks(&query)
+	return query, args
+}
+
+// Quote quote the identifier, each part of a dotted name is quoted
+func (qb *PgSQLQueryBuilder) Quote(name string) string {
+	return quoteQueryBuilderName(name, `"`, `"`)
+}
@@ -0,0 +1,214 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SQLiteQueryBuilder is the SQL build of sqlite
+type SQLiteQueryBuilder struct {
+	Tokens []string
+	Args   []interface{}
+}
+
+// Select will join the fields
+func (qb *SQLiteQueryBuilder) Select(fields ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "SELECT", strings.Join(fields, CommaSpace))
+	return qb
+}
+
+// ForUpdate do nothing, sqlite locks the database on write and has no FOR UPDATE
+func (qb *SQLiteQueryBuilder) ForUpdate() QueryBuilder {
+	return qb
+}
+
+// From join the tables
+func (qb *SQLiteQueryBuilder) From(tables ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "FROM", strings.Join(tables, CommaSpace))
+	return qb
+}
+
+// InnerJoin INNER JOIN the table
+func (qb *SQLiteQueryBuilder) InnerJoin(table string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "INNER JOIN", table)
+	return qb
+}
+
+// LeftJoin LEFT JOIN the table
+func (qb *SQLiteQueryBuilder) LeftJoin(table string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "LEFT JOIN", table)
+	return qb
+}
+
+// RightJoin RIGHT JOIN the table
+func (qb *SQLiteQueryBuilder) RightJoin(table string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "RIGHT JOIN", table)
+	return qb
+}
+
+// On join with on cond
+func (qb *SQLiteQueryBuilder) On(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "ON", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// Where join the Where cond
+func (qb *SQLiteQueryBuilder) Where(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "WHERE", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// And join the and cond
+func (qb *SQLiteQueryBuilder) And(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "AND", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// Or join the or cond
+func (qb *SQLiteQueryBuilder) Or(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "OR", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// In join the IN (vals)
+func (qb *SQLiteQueryBuilder) In(vals ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "IN", "(", strings.Join(vals, CommaSpace), ")")
+	return qb
+}
+
+// InArgs join the IN (?, ?) of the args, IN (NULL) without args, a slice arg is expanded by Build
+func (qb *SQLiteQueryBuilder) InArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "IN", "(", getQueryBuilderInMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// OrderBy join the Order by fields
+func (qb *SQLiteQueryBuilder) OrderBy(fields ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "ORDER BY", strings.Join(fields, CommaSpace))
+	return qb
+}
+
+// Asc join the asc
+func (qb *SQLiteQueryBuilder) Asc() QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "ASC")
+	return qb
+}
+
+// Desc join the desc
+func (qb *SQLiteQueryBuilder) Desc() QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "DESC")
+	return qb
+}
+
+// Limit join the limit num
+func (qb *SQLiteQueryBuilder) Limit(limit int) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "LIMIT", strconv.Itoa(limit))
+	return qb
+}
+
+// Offset join the offset num, sqlite needs a LIMIT before OFFSET
+func (qb *SQLiteQueryBuilder) Offset(offset int) QueryBuilder {
+	if !containsToken(qb.Tokens, "LIMIT") {
+		qb.Tokens = append(qb.Tokens, "LIMIT", "-1")
+	}
+	qb.Tokens = append(qb.Tokens, "OFFSET", strconv.Itoa(offset))
+	return qb
+}
+
+// GroupBy join the Group by fields
+func (qb *SQLiteQueryBuilder) GroupBy(fields ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "GROUP BY", strings.Join(fields, CommaSpace))
+	return qb
+}
+
+// Having join the Having cond
+func (qb *SQLiteQueryBuilder) Having(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "HAVING", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// Update join the update table
+func (qb *SQLiteQueryBuilder) Update(tables ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "UPDATE", strings.Join(tables, CommaSpace))
+	return qb
+}
+
+// Set join the set kv
+func (qb *SQLiteQueryBuilder) Set(kv ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "SET", strings.Join(kv, CommaSpace))
+	return qb
+}
+
+// Delete join the Delete tables
+func (qb *SQLiteQueryBuilder) Delete(tables ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "DELETE")
+	if len(tables) != 0 {
+		qb.Tokens = append(qb.Tokens, strings.Join(tables, CommaSpace))
+	}
+	return qb
+}
+
+// InsertInto join the insert SQL
+func (qb *SQLiteQueryBuilder) InsertInto(table string, fields ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "INSERT INTO", table)
+	if len(fields) != 0 {
+		fieldsStr := strings.Join(fields, CommaSpace)
+		qb.Tokens = append(qb.Tokens, "(", fieldsStr, ")")
+	}
+	return qb
+}
+
+// Values join the Values(vals)
+func (qb *SQLiteQueryBuilder) Values(vals ...string) QueryBuilder {
+	valsStr := strings.Join(vals, CommaSpace)
+	qb.Tokens = append(qb.Tokens, "VALUES", "(", valsStr, ")")
+	return qb
+}
+
+// ValuesArgs join the VALUES (?, ?) of the args
+func (qb *SQLiteQueryBuilder) ValuesArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "VALUES", "(", getQueryBuilderMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// Subquery join the sub as alias
+func (qb *SQLiteQueryBuilder) Subquery(sub string, alias string) string {
+	return fmt.Sprintf("(%s) AS %s", sub, alias)
+}
+
+// String join all Tokens
+func (qb *SQLiteQueryBuilder) String() string {
+	return strings.Join(qb.Tokens, " ")
+}
+
+// Build return the SQL and its args
+func (qb *SQLiteQueryBuilder) Build() (string, []interface{}) {
+	return expandQueryBuilderArgs(qb.String(), qb.Args)
+}
+
+// Quote quote the identifier, each part of a dotted name is quoted
+func (qb *SQLiteQueryBuilder) Quote(name string) string {
+	return quoteQueryBuilderName(name, "`", "`")
+}
@@ -0,0 +1,249 @@
+// Copyright 2014 beego Author. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SQLServerQueryBuilder is the SQL build of SQL Server.
+// Limit and Offset add OFFSET n ROWS FETCH NEXT m ROWS ONLY at the end, ForUpdate adds the
+// WITH (UPDLOCK, ROWLOCK) hint to each table of the first FROM.
+type SQLServerQueryBuilder struct {
+	Tokens    []string
+	Args      []interface{}
+	limit     int
+	offset    int
+	paged     bool
+	forUpdate bool
+	from      int      // index of the tables token of the first From
+	tables    []string // tables of the first From
+}
+
+// Select will join the fields
+func (qb *SQLServerQueryBuilder) Select(fields ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "SELECT", strings.Join(fields, CommaSpace))
+	return qb
+}
+
+// ForUpdate add the WITH (UPDLOCK, ROWLOCK) hint to each table of the first From
+func (qb *SQLServerQueryBuilder) ForUpdate() QueryBuilder {
+	qb.forUpdate = true
+	return qb
+}
+
+// From join the tables
+func (qb *SQLServerQueryBuilder) From(tables ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "FROM", strings.Join(tables, CommaSpace))
+	if qb.tables == nil {
+		qb.from, qb.tables = len(qb.Tokens)-1, tables
+	}
+	return qb
+}
+
+// InnerJoin INNER JOIN the table
+func (qb *SQLServerQueryBuilder) InnerJoin(table string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "INNER JOIN", table)
+	return qb
+}
+
+// LeftJoin LEFT JOIN the table
+func (qb *SQLServerQueryBuilder) LeftJoin(table string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "LEFT JOIN", table)
+	return qb
+}
+
+// RightJoin RIGHT JOIN the table
+func (qb *SQLServerQueryBuilder) RightJoin(table string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "RIGHT JOIN", table)
+	return qb
+}
+
+// On join with on cond
+func (qb *SQLServerQueryBuilder) On(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "ON", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// Where join the Where cond
+func (qb *SQLServerQueryBuilder) Where(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "WHERE", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// And join the and cond
+func (qb *SQLServerQueryBuilder) And(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "AND", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// Or join the or cond
+func (qb *SQLServerQueryBuilder) Or(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "OR", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// In join the IN (vals)
+func (qb *SQLServerQueryBuilder) In(vals ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "IN", "(", strings.Join(vals, CommaSpace), ")")
+	return qb
+}
+
+// InArgs join the IN (?, ?) of the args, IN (NULL) without args, a slice arg is expanded by Build
+func (qb *SQLServerQueryBuilder) InArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "IN", "(", getQueryBuilderInMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// OrderBy join the Order by fields
+func (qb *SQLServerQueryBuilder) OrderBy(fields ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "ORDER BY", strings.Join(fields, CommaSpace))
+	return qb
+}
+
+// Asc join the asc
+func (qb *SQLServerQueryBuilder) Asc() QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "ASC")
+	return qb
+}
+
+// Desc join the desc
+func (qb *SQLServerQueryBuilder) Desc() QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "DESC")
+	return qb
+}
+
+// Limit set the FETCH NEXT num ROWS ONLY
+func (qb *SQLServerQueryBuilder) Limit(limit int) QueryBuilder {
+	qb.limit = limit
+	qb.paged = true
+	return qb
+}
+
+// Offset set the OFFSET num ROWS
+func (qb *SQLServerQueryBuilder) Offset(offset int) QueryBuilder {
+	qb.offset = offset
+	qb.paged = true
+	return qb
+}
+
+// GroupBy join the Group by fields
+func (qb *SQLServerQueryBuilder) GroupBy(fields ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "GROUP BY", strings.Join(fields, CommaSpace))
+	return qb
+}
+
+// Having join the Having cond
+func (qb *SQLServerQueryBuilder) Having(cond string, args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "HAVING", cond)
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// Update join the update table
+func (qb *SQLServerQueryBuilder) Update(tables ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "UPDATE", strings.Join(tables, CommaSpace))
+	return qb
+}
+
+// Set join the set kv
+func (qb *SQLServerQueryBuilder) Set(kv ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "SET", strings.Join(kv, CommaSpace))
+	return qb
+}
+
+// Delete join the Delete tables
+func (qb *SQLServerQueryBuilder) Delete(tables ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "DELETE")
+	if len(tables) != 0 {
+		qb.Tokens = append(qb.Tokens, strings.Join(tables, CommaSpace))
+	}
+	return qb
+}
+
+// InsertInto join the insert SQL
+func (qb *SQLServerQueryBuilder) InsertInto(table string, fields ...string) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "INSERT INTO", table)
+	if len(fields) != 0 {
+		fieldsStr := strings.Join(fields, CommaSpace)
+		qb.Tokens = append(qb.Tokens, "(", fieldsStr, ")")
+	}
+	return qb
+}
+
+// Values join the Values(vals)
+func (qb *SQLServerQueryBuilder) Values(vals ...string) QueryBuilder {
+	valsStr := strings.Join(vals, CommaSpace)
+	qb.Tokens = append(qb.Tokens, "VALUES", "(", valsStr, ")")
+	return qb
+}
+
+// ValuesArgs join the VALUES (?, ?) of the args
+func (qb *SQLServerQueryBuilder) ValuesArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "VALUES", "(", getQueryBuilderMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
+// Subquery join the sub as alias
+func (qb *SQLServerQueryBuilder) Subquery(sub string, alias string) string {
+	return fmt.Sprintf("(%s) AS %s", sub, alias)
+}
+
+// String join all Tokens with the lock hint and the pagination
+func (qb *SQLServerQueryBuilder) String() string {
+	tokens := make([]string, 0, len(qb.Tokens)+8)
+	for i, token := range qb.Tokens {
+		if qb.forUpdate && qb.tables != nil && i == qb.from {
+			// the hint applies to the table it follows
+			hinted := make([]string, len(qb.tables))
+			for j, table := range qb.tables {
+				hinted[j] = table + " WITH (UPDLOCK, ROWLOCK)"
+			}
+			token = strings.Join(hinted, CommaSpace)
+		}
+		tokens = append(tokens, token)
+	}
+	if qb.paged {
+		// OFFSET FETCH needs an ORDER BY
+		if !containsToken(qb.Tokens, "ORDER BY") {
+			tokens = append(tokens, "ORDER BY", "(SELECT NULL)")
+		}
+		tokens = append(tokens, "OFFSET", strconv.Itoa(qb.offset), "ROWS")
+		if qb.limit > 0 {
+			tokens = append(tokens, "FETCH NEXT", strconv.Itoa(qb.limit), "ROWS ONLY")
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// Build return the SQL with the markers of the driver and its args
+func (qb *SQLServerQueryBuilder) Build() (string, []interface{}) {
+	query, args := expandQueryBuilderArgs(qb.String(), qb.Args)
+	new(dbBaseSqlserver).ReplaceMarks(&query)
+	return query, args
+}
+
+// Quote quote the identifier, each part of a dotted name is quoted
+func (qb *SQLServerQueryBuilder) Quote(name string) string {
+	return quoteQueryBuilderName(name, "[", "]")
+}
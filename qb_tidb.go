@@ -23,6 +23,7 @@ import (
 // TiDBQueryBuilder is the SQL build
 type TiDBQueryBuilder struct {
 	Tokens []string
+	Args   []interface{}
 }
 
 // Select will join the fields
@@ -62,26 +63,30 @@ func (qb *TiDBQueryBuilder) RightJoin(table string) QueryBuilder {
 }
 
 // On join with on cond
-func (qb *TiDBQueryBuilder) On(cond string) QueryBuilder {
+func (qb *TiDBQueryBuilder) On(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "ON", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // Where join the Where cond
-func (qb *TiDBQueryBuilder) Where(cond string) QueryBuilder {
+func (qb *TiDBQueryBuilder) Where(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "WHERE", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // And join the and cond
-func (qb *TiDBQueryBuilder) And(cond string) QueryBuilder {
+func (qb *TiDBQueryBuilder) And(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "AND", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
 // Or join the or cond
-func (qb *TiDBQueryBuilder) Or(cond string) QueryBuilder {
+func (qb *TiDBQueryBuilder) Or(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "OR", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
@@ -91,6 +96,13 @@ func (qb *TiDBQueryBuilder) In(vals ...string) QueryBuilder {
 	return qb
 }
 
+// InArgs join the IN (?, ?) of the args, IN (NULL) without args, a slice arg is expanded by Build
+func (qb *TiDBQueryBuilder) InArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "IN", "(", getQueryBuilderInMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
 // OrderBy join the Order by fields
 func (qb *TiDBQueryBuilder) OrderBy(fields ...string) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "ORDER BY", strings.Join(fields, CommaSpace))
@@ -128,8 +140,9 @@ func (qb *TiDBQueryBuilder) GroupBy(fields ...string) QueryBuilder {
 }
 
 // Having join the Having cond
-func (qb *TiDBQueryBuilder) Having(cond string) QueryBuilder {
+func (qb *TiDBQueryBuilder) Having(cond string, args ...interface{}) QueryBuilder {
 	qb.Tokens = append(qb.Tokens, "HAVING", cond)
+	qb.Args = append(qb.Args, args...)
 	return qb
 }
 
@@ -171,6 +184,13 @@ func (qb *TiDBQueryBuilder) Values(vals ...string) QueryBuilder {
 	return qb
 }
 
+// ValuesArgs join the VALUES (?, ?) of the args
+func (qb *TiDBQueryBuilder) ValuesArgs(args ...interface{}) QueryBuilder {
+	qb.Tokens = append(qb.Tokens, "VALUES", "(", getQueryBuilderMarks(len(args)), ")")
+	qb.Args = append(qb.Args, args...)
+	return qb
+}
+
 // Subquery join the sub as alias
 func (qb *TiDBQueryBuilder) Subquery(sub string, alias string) string {
 	return fmt.Sprintf("(%s) AS %s", sub, alias)
@@ -180,3 +200,13 @@ func (qb *TiDBQueryBuilder) Subquery(sub string, alias string) string {
 func (qb *TiDBQueryBuilder) String() string {
 	return strings.Join(qb.Tokens, " ")
 }
+
+// Build return the SQL and its args
+func (qb *TiDBQueryBuilder) Build() (string, []interface{}) {
+	return expandQueryBuilderArgs(qb.String(), qb.Args)
+}
+
+// Quote quote the identifier, each part of a dotted name is quoted
+func (qb *TiDBQueryBuilder) Quote(name string) string {
+	return quoteQueryBuilderName(name, "`", "`")
+}
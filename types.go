@@ -49,6 +49,11 @@ type Ormer interface {
 	// Like Read(), but with "FOR UPDATE" clause, useful in transaction.
 	// Some databases are not support this feature.
 	ReadForUpdate(md interface{}, cols ...string) error
+	// Like ReadForUpdate(), but with the given lock, Of is ignored.
+	// for example:
+	//	err = Ormer.ReadWithLock(u, orm.RowLock{Wait: orm.NoWait})
+	//	err = Ormer.ReadWithLock(u, orm.RowLock{Share: true})
+	ReadWithLock(md interface{}, lock RowLock, cols ...string) error
 	// Try to read a row from the database, or insert one if it doesn't exist
 	ReadOrCreate(md interface{}, col1 string, cols ...string) (bool, int64, error)
 	// insert model data to database
@@ -64,6 +69,12 @@ type Ormer interface {
 	InsertOrUpdate(md interface{}, colConflitAndArgs ...string) (int64, error)
 	// insert some models to database
 	InsertMulti(bulk int, mds interface{}) (int64, error)
+	// update some models to database with different values per row.
+	// bulk models are updated by one statement, cols work like Update, auto_now fields are always set.
+	// for example:
+	//	users[0].Status, users[1].Status = 1, 2
+	//	num, err = Ormer.UpdateMulti(100, users, "Status")
+	UpdateMulti(bulk int, mds interface{}, cols ...string) (int64, error)
 	// update model to database.
 	// cols set the columns those want to update.
 	// find model by Id(pk) field and update columns specified by fields, if cols is null then update all columns
@@ -89,15 +100,38 @@ type Ormer interface {
 	//args[3] string order  for example : "-Id"
 	// make sure the relation is defined in model struct tags.
 	LoadRelated(md interface{}, name string, args ...interface{}) (int64, error)
+	// load related models to every model of a slice, one query per relation.
+	// name can be a related path, the loaded count of the last relation is returned.
+	//
+	// example:
+	// 	Ormer.LoadRelatedMulti(posts, "Tags")
+	// 	Ormer.LoadRelatedMulti(&posts, "Comments__Author")
+	LoadRelatedMulti(mds interface{}, name string) (int64, error)
 	// create a models to models queryer
 	// for example:
 	// 	post := Post{Id: 4}
 	// 	m2m := Ormer.QueryM2M(&post, "Tags")
+	// with WithTenant, the queryer of a model of another tenant returns ErrTenant.
 	QueryM2M(md interface{}, name string) QueryM2Mer
 	// return a QuerySeter for table operations.
 	// table name can be string or struct.
 	// e.g. QueryTable("user"), QueryTable(&user{}) or QueryTable((*User)(nil)),
 	QueryTable(ptrStructOrTableName interface{}) QuerySeter
+	// return an Ormer scoped to the tenant id, the Ormer itself is unchanged.
+	// models with an orm:"tenant" field only see and write the rows of the tenant:
+	// the tenant column is filled on insert, added to the where of Read, Update,
+	// Delete, QueryTable, QueryM2M and LoadRelated, and models of another tenant
+	// are rejected with ErrTenant. InsertOrUpdate of such models returns ErrTenantUpsert.
+	// for example:
+	//	o := orm.NewOrm().WithTenant(42)
+	//	o.QueryTable("user").All(&users) // WHERE tenant_id = 42
+	WithTenant(id interface{}) Ormer
+	// return an Ormer whose tables live in schema, the Ormer itself is unchanged.
+	// the schema replaces the one of RegisterModelWithSchema in every table and join.
+	// for example:
+	//	o := orm.NewOrm().UsingSchema("tenant_42")
+	//	o.QueryTable("user").All(&users) // FROM "tenant_42"."user" T0
+	UsingSchema(schema string) Ormer
 	// switch to another registered database driver by given name.
 	Using(name string) error
 	// begin transaction
@@ -126,6 +160,10 @@ type Ormer interface {
 	// for example:
 	//	 ormer.Raw("UPDATE `user` SET `user_name` = ? WHERE `user_name` = ?", "slene", "testing").Exec()
 	//	// update user testing's name to slene
+	// a single map or struct arg binds the named params :name or @name, repeated names share the value:
+	//	 ormer.Raw("UPDATE user SET user_name = :name WHERE id = :id", map[string]interface{}{"name": "slene", "id": 1}).Exec()
+	// a slice or array arg is expanded to one marker per element, []byte is a scalar:
+	//	 ormer.Raw("SELECT * FROM user WHERE id IN (?)", []int{1, 2, 3}) // id IN (?, ?, ?)
 	Raw(query string, args ...interface{}) RawSeter
 	Driver() Driver
 }
@@ -155,6 +193,15 @@ type QuerySeter interface {
 	// add NOT condition to querySeter.
 	// have the same usage as Filter
 	Exclude(string, ...interface{}) QuerySeter
+	// add conditions for the non-zero fields of an example model, exact by default.
+	// opts set other operators per field, zero fields to include and range bounds.
+	// for example:
+	//	qs.FilterByExample(&User{UserName: "sle", Status: 1}, &orm.ExampleOptions{
+	//		Operators: map[string]string{"UserName": "istartswith"},
+	//		Include:   []string{"IsStaff"},
+	//	})
+	//	// WHERE user_name LIKE 'sle%' AND status = 1 AND is_staff = false
+	FilterByExample(md interface{}, opts *ExampleOptions) QuerySeter
 	// set condition to QuerySeter.
 	// sql's where condition
 	//	cond := orm.NewCondition()
@@ -162,6 +209,21 @@ type QuerySeter interface {
 	//	//sql-> WHERE T0.`profile_id` IS NOT NULL AND NOT T0.`Status` IN (?) OR T1.`age` >  2000
 	//	num, err := qs.SetCond(cond1).Count()
 	SetCond(*Condition) QuerySeter
+	// skip the default ordering and condition declared by the model
+	// with TableOrdering and TableDefaultCond.
+	// for example:
+	//	qs.Unscoped().Filter("deleted", true).All(&users)
+	Unscoped() QuerySeter
+	// apply the named scopes declared by the model, in order.
+	// the model declares them by a Scopes method:
+	//	func (u *User) Scopes() map[string]func(orm.QuerySeter) orm.QuerySeter {
+	//		return map[string]func(orm.QuerySeter) orm.QuerySeter{
+	//			"active": func(qs orm.QuerySeter) orm.QuerySeter { return qs.Filter("is_active", true) },
+	//		}
+	//	}
+	// for example:
+	//	qs.Scope("active", "recent").Filter("status", 1).All(&users)
+	Scope(names ...string) QuerySeter
 	// get condition from QuerySeter.
 	// sql's where condition
 	//  cond := orm.NewCondition()
@@ -201,6 +263,32 @@ type QuerySeter interface {
 	//	qs.RelatedSel("profile").One(&user)
 	//	user.Profile.Age = 32
 	RelatedSel(params ...interface{}) QuerySeter
+	// load related models of all results with one query per relation
+	// after All or One, reverse and m2m relations are supported.
+	// for example:
+	//	qs.PrefetchRelated("Tags", "User__Profile").All(&posts)
+	//	posts[0].Tags[0].Name
+	PrefetchRelated(names ...string) QuerySeter
+	// load only the given fields, related paths are allowed.
+	// pk and relation columns are always loaded.
+	// a model embedding UnloadedFields remembers the fields not loaded,
+	// Update without cols keeps their stored values.
+	// for example:
+	//	qs.RelatedSel().Only("UserName", "Profile__Age").All(&users)
+	Only(fields ...string) QuerySeter
+	// skip loading the given fields, related paths are allowed.
+	// a model embedding UnloadedFields remembers the fields not loaded,
+	// Update without cols keeps their stored values.
+	// for example:
+	//	qs.Defer("Content").All(&posts)
+	Defer(fields ...string) QuerySeter
+	// get the field names of the model loaded with Only/Defer, all fields without them.
+	// for example:
+	//	qs = qs.Defer("Content")
+	//	qs.All(&posts)
+	//	posts[0].Title = "new title"
+	//	o.Update(posts[0], qs.LoadedFields()...) // Content is not overwritten
+	LoadedFields() []string
 	// Set Distinct
 	// for example:
 	//  o.QueryTable("policy").Filter("Groups__Group__Users__User", user).
@@ -208,9 +296,18 @@ type QuerySeter interface {
 	//    All(&permissions)
 	Distinct() QuerySeter
 	// set FOR UPDATE to query.
+	// wait can be orm.NoWait or orm.SkipLocked, it returns an error where unsupported.
 	// for example:
 	//  o.QueryTable("user").Filter("uid", uid).ForUpdate().All(&users)
-	ForUpdate() QuerySeter
+	//  o.QueryTable("job").Filter("status", 0).ForUpdate(orm.SkipLocked).Limit(10).All(&jobs)
+	ForUpdate(wait ...LockWait) QuerySeter
+	// set FOR SHARE to query, like ForUpdate.
+	ForShare(wait ...LockWait) QuerySeter
+	// set FOR UPDATE OF table to query, only rows of table are locked.
+	// table is the model table name or a related name.
+	// for example:
+	//  o.QueryTable("post").RelatedSel("user").ForUpdateOf("post").All(&posts)
+	ForUpdateOf(table string, wait ...LockWait) QuerySeter
 	// return QuerySeter execution result number
 	// for example:
 	//	num, err = qs.Filter("profile__age__gt", 28).Count()
@@ -232,6 +329,17 @@ type QuerySeter interface {
 	//	num ,err = qs.Filter("user_name__in", "testing1", "testing2").Delete()
 	// 	//delete two user  who's name is testing1 or testing2
 	Delete() (int64, error)
+	// execute update with parameters and read the updated rows into container.
+	// RETURNING or OUTPUT is used where supported, otherwise the rows are
+	// selected and updated in a transaction.
+	// for example:
+	//	var users []*User
+	//	num, err = qs.Filter("status", 0).UpdateReturning(Params{"status": 1}, &users)
+	UpdateReturning(values Params, container interface{}) (int64, error)
+	// execute delete and read the deleted rows into container, like UpdateReturning.
+	// for example:
+	//	num, err = qs.Filter("status", 2).DeleteReturning(&users)
+	DeleteReturning(container interface{}) (int64, error)
 	// return a insert queryer.
 	// it can be used in times.
 	// example:
@@ -252,6 +360,28 @@ type QuerySeter interface {
 	//	var user User
 	//	qs.One(&user) //user.UserName == "slene"
 	One(container interface{}, cols ...string) error
+	// walk all rows in batches of size ordered by primary key.
+	// batch is a []*Model, paging uses pk > last pk instead of OFFSET.
+	// returning an error from fn stops the walk and returns the error.
+	// for example:
+	//	err := qs.Filter("status", 1).InBatches(100, func(batch interface{}) error {
+	//		for _, user := range batch.([]*User) {
+	//			...
+	//		}
+	//		return nil
+	//	})
+	InBatches(size int, fn func(batch interface{}) error) error
+	// get the sql and args of All/Count/Update/Delete without executing it.
+	// OpUpdate needs the update values.
+	// for example:
+	//	query, args, err := qs.Filter("profile__age__gt", 28).ToSQL(orm.OpAll)
+	//	query, args, err = qs.ToSQL(orm.OpUpdate, orm.Params{"status": 1})
+	ToSQL(op QueryOp, values ...Params) (string, []interface{}, error)
+	// run the EXPLAIN of the database on the All query and return the plan rows.
+	// analyze executes the query where supported (EXPLAIN ANALYZE).
+	// for example:
+	//	plan, err := qs.Filter("status", 1).Explain(false)
+	Explain(analyze bool) ([]Params, error)
 	// query all data and map to []map[string]interface.
 	// expres means condition expression.
 	// it converts data to []map[column]value.
@@ -325,8 +455,29 @@ type QueryM2Mer interface {
 }
 
 // RawPreparer raw query statement
+// for example:
+//
+//	pre, err := dORM.Raw("SELECT * FROM user WHERE id = ?").Prepare()
+//	err = pre.SetArgs(1).QueryRow(&user)
+//	err = pre.SetArgs(2).QueryRow(&user)
 type RawPreparer interface {
 	Exec(...interface{}) (sql.Result, error)
+	// set the args of the queries, each query of the statement uses them
+	SetArgs(...interface{}) RawPreparer
+	// query data and map to container, see RawSeter.QueryRow
+	QueryRow(containers ...interface{}) error
+	// query data rows and map to container, see RawSeter.QueryRows
+	QueryRows(containers ...interface{}) (int64, error)
+	// query data to []map[string]interface, see RawSeter.Values
+	Values(container *[]Params, cols ...string) (int64, error)
+	// query data to [][]interface, see RawSeter.ValuesList
+	ValuesList(container *[]ParamsList, cols ...string) (int64, error)
+	// query data to []interface, see RawSeter.ValuesFlat
+	ValuesFlat(container *ParamsList, cols ...string) (int64, error)
+	// query all rows into map[string]interface, see RawSeter.RowsToMap
+	RowsToMap(result *Params, keyCol, valueCol string) (int64, error)
+	// query all rows into struct, see RawSeter.RowsToStruct
+	RowsToStruct(ptrStruct interface{}, keyCol, valueCol string) (int64, error)
 	Close() error
 }
 
@@ -350,7 +501,18 @@ type RawSeter interface {
 	//	var names []int
 	//	query = fmt.Sprintf("SELECT 'id','name' FROM %suser%s", Q, Q)
 	//	num, err = dORM.Raw(query).QueryRows(&ids,&names) // ids=>{1,2},names=>{"nobody","slene"}
+	// columns prefixed with RawColumnSep fill nested structs and related models,
+	// rows of one pk fill one struct when a prefix is a slice:
+	//	query = `SELECT u.id, u.user_name, p.id AS "posts.id", p.title AS "posts.title" FROM user u JOIN post p ON p.user_id = u.id`
+	//	num, err = dORM.Raw(query).QueryRows(&users) // users[0].Posts => {{ID: 1, Title: "..."}, ...}
 	QueryRows(containers ...interface{}) (int64, error)
+	// query the result sets of a query like a stored procedure call, each one maps to the next container.
+	// the result is the count of all rows, see QueryRows for the containers.
+	//	var users []User
+	//	var counts []int
+	//	num, err = dORM.Raw("CALL user_stats(?)", 1).QueryMulti(&users, &counts)
+	QueryMulti(containers ...interface{}) (int64, error)
+	// set the args of the query, replacing the args of Raw
 	SetArgs(...interface{}) RawSeter
 	// query data to []map[string]interface
 	// see QuerySeter's Values
@@ -388,6 +550,10 @@ type RawSeter interface {
 	// for example:
 	// 	pre, err := dORM.Raw("INSERT INTO tag (name) VALUES (?)").Prepare()
 	// 	r, err := pre.Exec("name1") // INSERT INTO tag (name) VALUES (`name1`)
+	// a query with named params is prepared and executed with a map or struct,
+	// without it :name and @name are sent to the database as they are:
+	// 	pre, err := dORM.Raw("INSERT INTO tag (name) VALUES (:name)", &Tag{}).Prepare()
+	// 	r, err := pre.Exec(&Tag{Name: "name1"})
 	Prepare() (RawPreparer, error)
 }
 
@@ -436,19 +602,30 @@ type txEnder interface {
 
 // base database struct
 type dbBaser interface {
-	Read(dbQuerier, *modelInfo, reflect.Value, *time.Location, []string, bool) error
+	Read(dbQuerier, *modelInfo, reflect.Value, *time.Location, []string, *RowLock, interface{}) error
 	Insert(dbQuerier, *modelInfo, reflect.Value, *time.Location) (int64, error)
 	InsertOrUpdate(dbQuerier, *modelInfo, reflect.Value, *alias, ...string) (int64, error)
 	InsertMulti(dbQuerier, *modelInfo, reflect.Value, int, *time.Location) (int64, error)
+	UpdateMulti(dbQuerier, *modelInfo, reflect.Value, int, *alias, []string, interface{}) (int64, error)
+	UpdateMultiSQL(*modelInfo, []string, [][]interface{}, *alias, interface{}) (string, []interface{})
 	InsertValue(dbQuerier, *modelInfo, bool, []string, []interface{}) (int64, error)
 	InsertStmt(stmtQuerier, *modelInfo, reflect.Value, *time.Location) (int64, error)
-	Update(dbQuerier, *modelInfo, reflect.Value, *time.Location, []string) (int64, error)
-	Delete(dbQuerier, *modelInfo, reflect.Value, *time.Location, []string) (int64, error)
+	Update(dbQuerier, *modelInfo, reflect.Value, *time.Location, []string, interface{}) (int64, error)
+	Delete(dbQuerier, *modelInfo, reflect.Value, *time.Location, []string, interface{}) (int64, error)
 	ReadBatch(dbQuerier, *querySet, *modelInfo, *Condition, interface{}, *time.Location, []string) (int64, error)
 	SupportUpdateJoin() bool
 	UpdateBatch(dbQuerier, *querySet, *modelInfo, *Condition, Params, *time.Location) (int64, error)
 	DeleteBatch(dbQuerier, *querySet, *modelInfo, *Condition, *time.Location) (int64, error)
+	UpdateBatchReturning(dbQuerier, *querySet, *modelInfo, *Condition, Params, interface{}, *time.Location) (int64, error)
+	DeleteBatchReturning(dbQuerier, *querySet, *modelInfo, *Condition, interface{}, *time.Location) (int64, error)
+	ReturningSQL(*modelInfo, bool) (string, string)
 	Count(dbQuerier, *querySet, *modelInfo, *Condition, *time.Location) (int64, error)
+	ReadBatchSQL(*querySet, *modelInfo, *Condition, *time.Location, []string) (string, []interface{}, error)
+	CountSQL(*querySet, *modelInfo, *Condition, *time.Location) (string, []interface{})
+	UpdateBatchSQL(*querySet, *modelInfo, *Condition, Params, *time.Location) (string, []interface{})
+	DeleteBatchSQL(*querySet, *modelInfo, *Condition, *time.Location) (string, []interface{})
+	Explain(dbQuerier, string, []interface{}, bool) ([]Params, error)
+	RowLockSQL(*RowLock, []*dbTable) (string, string, error)
 	OperatorSQL(string) string
 	GenerateOperatorSQL(*modelInfo, *fieldInfo, string, []interface{}, *time.Location) (string, []interface{})
 	GenerateOperatorLeftCol(*fieldInfo, string, *string)
@@ -464,6 +641,9 @@ type dbBaser interface {
 	DbTypes() map[string]string
 	GetTables(dbQuerier) (map[string]bool, error)
 	GetColumns(dbQuerier, string) (map[string][3]string, error)
+	GetSchemaTables(dbQuerier, string) (map[string]bool, error)
+	GetSchemaColumns(dbQuerier, string, string) (map[string][3]string, error)
+	CreateSchemaSQL(string) (string, error)
 	ShowTablesQuery() string
 	ShowColumnsQuery(string) string
 	IndexExists(dbQuerier, string, string) bool